@@ -0,0 +1,148 @@
+Feature: Authors
+
+  Background: Reset mock server
+    Given reset mock server
+
+  Scenario: Create an author
+    When API "POST" request is sent to "/api/v1/authors" with payload
+    """json
+    {
+      "name": "Donald E. Knuth"
+    }
+    """
+    Then API response status code is 200 and payload is
+    """json
+    {
+        "id": 1,
+        "name": "Donald E. Knuth"
+    }
+    """
+    And SQL query "SELECT name FROM myschema.authors" result is equal to
+    """json
+    [
+       {
+          "name":"Donald E. Knuth"
+       }
+    ]
+    """
+
+  Scenario: List the authors
+    Given SQL command
+    """
+    INSERT INTO myschema.authors (id, name, created_at, updated_at)
+    VALUES (2001, 'Donald E. Knuth', now(), now()),
+           (2002, 'Brian W. Kernighan', now(), now());
+    """
+    When API "GET" request is sent to "/api/v1/authors" without payload
+    Then API response status code is 200 and payload is
+    """json
+    {
+        "authors": [
+            {
+                "id": 2002,
+                "name": "Brian W. Kernighan"
+            },
+            {
+                "id": 2001,
+                "name": "Donald E. Knuth"
+            }
+        ]
+    }
+    """
+
+  Scenario: Get an author that does not exist
+    When API "GET" request is sent to "/api/v1/authors/2999" without payload
+    Then API response status code is 404 and payload is
+    """json
+    {
+        "code": "AUTHOR_NOT_FOUND",
+        "message": "Not Found. Error getting author",
+        "error": "author not found"
+    }
+    """
+
+  Scenario: List the books of an author
+    Given SQL command
+    """
+    INSERT INTO myschema.authors (id, name, created_at, updated_at)
+    VALUES (2001, 'Donald E. Knuth', now(), now()),
+           (2002, 'Brian W. Kernighan', now(), now());
+    INSERT INTO myschema.books (id, isbn, title, created_at, updated_at)
+    VALUES (1001, '9780061964367', 'The Art of Computer Programming', now(), now()),
+           (1002, '9780131103627', 'The C Programming Language', now(), now());
+    INSERT INTO myschema.book_authors (book_id, author_id)
+    VALUES (1001, 2001),
+           (1002, 2002);
+    """
+    When API "GET" request is sent to "/api/v1/authors/2001/books" without payload
+    Then API response status code is 200 and payload is
+    """json
+    {
+        "books": [
+            {
+                "isbn": "9780061964367",
+                "title": "The Art of Computer Programming",
+                "authors": [
+                    {
+                        "id": 2001,
+                        "name": "Donald E. Knuth"
+                    }
+                ]
+            }
+        ]
+    }
+    """
+
+  Scenario: Upgrading a database with the authors column keeps the authors of the books
+    Given the migrations after version 2 are applied again after SQL command
+    """
+    ALTER TABLE myschema.books ADD COLUMN authors text[];
+    INSERT INTO myschema.books (id, isbn, title, authors, created_at, updated_at)
+    VALUES (3001, '9780201038019', 'Fundamental Algorithms', ARRAY['Donald E. Knuth'], now(), now()),
+           (3002, '9780131103627', 'The C Programming Language', ARRAY['Brian W. Kernighan', 'Dennis M. Ritchie'], now(), now()),
+           (3003, '9780201038026', 'Seminumerical Algorithms', ARRAY['Donald E. Knuth'], now(), now()),
+           (3004, '9780201558029', 'Concrete Mathematics', NULL, now(), now());
+    """
+    Then SQL query "SELECT book_authors.book_id, authors.id, authors.name FROM myschema.book_authors JOIN myschema.authors ON authors.id = book_authors.author_id ORDER BY book_authors.book_id, authors.id" result is equal to
+    """json
+    [
+       {
+          "book_id": 3001,
+          "id": 1,
+          "name": "Donald E. Knuth"
+       },
+       {
+          "book_id": 3002,
+          "id": 2,
+          "name": "Brian W. Kernighan"
+       },
+       {
+          "book_id": 3002,
+          "id": 3,
+          "name": "Dennis M. Ritchie"
+       },
+       {
+          "book_id": 3003,
+          "id": 1,
+          "name": "Donald E. Knuth"
+       }
+    ]
+    """
+    When API "GET" request is sent to "/api/v1/books/9780131103627" without payload
+    Then API response status code is 200 and payload is
+    """json
+    {
+        "isbn": "9780131103627",
+        "title": "The C Programming Language",
+        "authors": [
+            {
+                "id": 2,
+                "name": "Brian W. Kernighan"
+            },
+            {
+                "id": 3,
+                "name": "Dennis M. Ritchie"
+            }
+        ]
+    }
+    """
@@ -1,27 +1,128 @@
 Feature: Create book
 
-  Background: Clean database
+  Background: Reset mock server
+    Given reset mock server
+
+  Scenario: Create a new book successfully
     Given SQL command
     """
-    DELETE FROM myschema.books;
+    INSERT INTO myschema.authors (id, name, created_at, updated_at)
+    VALUES (2001, 'Donald E. Knuth', now(), now());
+    """
+    And a mock server request with method: "GET" and url: "https://api.isbncheck.com/isbn/9780061964367"
+    And a mock server response with status 200 and body
+    """json
+    {
+       "id": "0-061-96436-0"
+    }
+    """
+    And a mock server request with method: "POST" and url: "https://api.gmail.com/send-email" and body
+    """json
+    {
+      "email" : "helloworld@gmail.com",
+      "subject" : "New book: The Art of Computer Programming",
+      "body" : "The Art of Computer Programming (9780061964367) by Donald E. Knuth has been added to the catalog.",
+      "book" : {
+        "isbn" : "9780061964367",
+        "title" : "The Art of Computer Programming",
+        "authors" : ["Donald E. Knuth"],
+        "total_pages" : 672,
+        "publisher" : "Addison-Wesley",
+        "publication_date" : "1997-07-17",
+        "language" : "en",
+        "description" : "Fundamental Algorithms"
+      }
+    }
+    """
+    And a mock server response with status 200 and body
+    """json
+    {
+       "status": "OK"
+    }
+    """
+    When API "POST" request is sent to "/api/v1/createBook" with payload
+    """json
+    {
+      "isbn": "0-061-96436-0",
+      "title": "The Art of Computer Programming",
+      "author_ids": [2001],
+      "total_pages": 672,
+      "publisher": "Addison-Wesley",
+      "publication_date": "1997-07-17",
+      "language": "en",
+      "description": "Fundamental Algorithms"
+    }
+    """
+    Then API response status code is 200 and payload is
+    """json
+    {
+        "isbn": "9780061964367",
+        "title": "The Art of Computer Programming",
+        "authors": [
+            {
+                "id": 2001,
+                "name": "Donald E. Knuth"
+            }
+        ],
+        "total_pages": 672,
+        "publisher": "Addison-Wesley",
+        "publication_date": "1997-07-17",
+        "language": "en",
+        "description": "Fundamental Algorithms"
+    }
+    """
+    And SQL query "SELECT id, isbn, title, total_pages, publisher, publication_date::text AS publication_date, language, description FROM myschema.books WHERE isbn = '9780061964367'" result is equal to
+    """json
+    [
+       {
+          "id":1,
+          "isbn":"9780061964367",
+          "title":"The Art of Computer Programming",
+          "total_pages":672,
+          "publisher":"Addison-Wesley",
+          "publication_date":"1997-07-17",
+          "language":"en",
+          "description":"Fundamental Algorithms"
+       }
+    ]
+    """
+    And SQL query "SELECT ba.author_id FROM myschema.book_authors ba JOIN myschema.books b ON b.id = ba.book_id WHERE b.isbn = '9780061964367'" result is equal to
+    """json
+    [
+       {
+          "author_id":2001
+       }
+    ]
+    """
+    And SQL query "SELECT event_type, attempts, last_error, delivered_at IS NOT NULL AS delivered FROM myschema.outbox_events" result is eventually equal to
+    """json
+    [
+       {
+          "event_type":"book_created_notification",
+          "attempts":0,
+          "last_error":"",
+          "delivered":true
+       }
+    ]
     """
-    And reset mock server
 
-  Scenario: Create a new book successfully
-    Given a mock server request with method: "GET" and url: "https://api.isbncheck.com/isbn/0-061-96436-1"
+  Scenario: Create a new book with only the required fields
+    Given a mock server request with method: "GET" and url: "https://api.isbncheck.com/isbn/9780201038019"
     And a mock server response with status 200 and body
     """json
     {
-       "id": "0-061-96436-1"
+       "id": "0-201-03801-3"
     }
     """
     And a mock server request with method: "POST" and url: "https://api.gmail.com/send-email" and body
     """json
     {
       "email" : "helloworld@gmail.com",
+      "subject" : "New book: Fundamental Algorithms",
+      "body" : "Fundamental Algorithms (9780201038019) has been added to the catalog.",
       "book" : {
-        "isbn" : "0-061-96436-1",
-        "title" : "The Art of Computer Programming"
+        "isbn" : "9780201038019",
+        "title" : "Fundamental Algorithms"
       }
     }
     """
@@ -31,6 +132,245 @@ Feature: Create book
        "status": "OK"
     }
     """
+    When API "POST" request is sent to "/api/v1/books" with payload
+    """json
+    {
+      "isbn": "0-201-03801-3",
+      "title": "Fundamental Algorithms"
+    }
+    """
+    Then API response status code is 200 and payload is
+    """json
+    {
+        "isbn": "9780201038019",
+        "title": "Fundamental Algorithms"
+    }
+    """
+
+  Scenario: Create a book notifies the subscribers through their channel
+    Given SQL command
+    """
+    INSERT INTO myschema.notification_subscribers (event, channel, address)
+    VALUES ('book_created', 'webhook', 'https://hooks.example.com/books'),
+           ('book_created', 'smtp', 'disabled@example.com');
+    INSERT INTO myschema.notification_subscribers (event, channel, address, active)
+    VALUES ('book_created', 'webhook', 'https://hooks.example.com/inactive', false);
+    """
+    And a mock server request with method: "GET" and url: "https://api.isbncheck.com/isbn/9780201038019"
+    And a mock server response with status 200 and body
+    """json
+    {
+       "id": "0-201-03801-3"
+    }
+    """
+    And a mock server request with method: "POST" and url: "https://api.gmail.com/send-email"
+    And a mock server response with status 200 and body
+    """json
+    {
+       "status": "OK"
+    }
+    """
+    And a mock server request with method: "POST" and url: "https://hooks.example.com/books" and body
+    """json
+    {
+      "event" : "book_created",
+      "subject" : "New book: Fundamental Algorithms",
+      "body" : "Fundamental Algorithms (9780201038019) has been added to the catalog.",
+      "book" : {
+        "isbn" : "9780201038019",
+        "title" : "Fundamental Algorithms"
+      }
+    }
+    """
+    And a mock server response with status 204 and body
+    """
+    """
+    When API "POST" request is sent to "/api/v1/createBook" with payload
+    """json
+    {
+      "isbn": "0-201-03801-3",
+      "title": "Fundamental Algorithms"
+    }
+    """
+    Then API response status code is 200 and payload is
+    """json
+    {
+        "isbn": "9780201038019",
+        "title": "Fundamental Algorithms"
+    }
+    """
+    And SQL query "SELECT payload->>'channel' AS channel, payload->>'address' AS address, attempts, delivered_at IS NOT NULL AS delivered FROM myschema.outbox_events ORDER BY id" result is eventually equal to
+    """json
+    [
+       {
+          "channel":"email_api",
+          "address":"helloworld@gmail.com",
+          "attempts":0,
+          "delivered":true
+       },
+       {
+          "channel":"webhook",
+          "address":"https://hooks.example.com/books",
+          "attempts":0,
+          "delivered":true
+       }
+    ]
+    """
+
+  Scenario: Create a book whose ISBN was already checked does not call the external ISBN service
+    Given SQL command
+    """
+    INSERT INTO myschema.isbn_check_cache (isbn, valid, canonical_id, expires_at)
+    VALUES ('9780201038019', true, '0-201-03801-3', now() + interval '1 hour');
+    """
+    And a mock server request with method: "POST" and url: "https://api.gmail.com/send-email"
+    And a mock server response with status 200 and body
+    """json
+    {
+       "status": "OK"
+    }
+    """
+    When API "POST" request is sent to "/api/v1/createBook" with payload
+    """json
+    {
+      "isbn": "0-201-03801-3",
+      "title": "Fundamental Algorithms"
+    }
+    """
+    Then API response status code is 200 and payload is
+    """json
+    {
+        "isbn": "9780201038019",
+        "title": "Fundamental Algorithms"
+    }
+    """
+    When API "GET" request is sent to "/internal/metrics" without payload
+    Then API response status code is 200 and payload is
+    """json
+    {
+        "isbn_check_cache": {
+            "hits": 1,
+            "misses": 0
+        }
+    }
+    """
+
+  @isbn_cache_memory
+  Scenario: Create a book twice checks its ISBN once with the memory cache
+    Given a mock server request with method: "GET" and url: "https://api.isbncheck.com/isbn/9780201038019"
+    And a mock server response with status 200 and body
+    """json
+    {
+       "id": "0-201-03801-3"
+    }
+    """
+    And a mock server request with method: "POST" and url: "https://api.gmail.com/send-email"
+    And a mock server response with status 200 and body
+    """json
+    {
+       "status": "OK"
+    }
+    """
+    When API "POST" request is sent to "/api/v1/createBook" with payload
+    """json
+    {
+      "isbn": "0-201-03801-3",
+      "title": "Fundamental Algorithms"
+    }
+    """
+    Then API response status code is 200 and payload is
+    """json
+    {
+        "isbn": "9780201038019",
+        "title": "Fundamental Algorithms"
+    }
+    """
+    # The external ISBN service is not mocked anymore, so the second check must be served by the cache
+    Given reset mock server
+    When API "POST" request is sent to "/api/v1/createBook" with payload
+    """json
+    {
+      "isbn": "0-201-03801-3",
+      "title": "Fundamental Algorithms"
+    }
+    """
+    Then API response status code is 409 and payload is
+    """json
+    {
+        "code": "BOOK_ALREADY_EXISTS",
+        "message": "Conflict. Error creating book",
+        "error": "book already exist"
+    }
+    """
+    When API "GET" request is sent to "/internal/metrics" without payload
+    Then API response status code is 200 and payload is
+    """json
+    {
+        "isbn_check_cache": {
+            "hits": 1,
+            "misses": 1
+        }
+    }
+    """
+    And SQL query "SELECT count(*) AS entries FROM myschema.isbn_check_cache" result is equal to
+    """json
+    [
+       {
+          "entries":0
+       }
+    ]
+    """
+
+  @isbn_cache_memory
+  Scenario: Create a book rejected by the external ISBN service caches the rejection in memory
+    Given a mock server request with method: "GET" and url: "https://api.isbncheck.com/isbn/9780061964367"
+    And a mock server response with status 404 and body
+    """json
+    {}
+    """
+    When API "POST" request is sent to "/api/v1/createBook" with payload
+    """json
+    {
+      "isbn": "0-061-96436-0",
+      "title": "The Art of Computer Programming"
+    }
+    """
+    Then API response status code is 422 and payload is
+    """json
+    {
+        "code": "INVALID_ISBN",
+        "message": "Unprocessable Entity. Error creating book",
+        "error": "invalid isbn: 9780061964367 is not valid based on external service"
+    }
+    """
+    Given reset mock server
+    When API "POST" request is sent to "/api/v1/createBook" with payload
+    """json
+    {
+      "isbn": "0-061-96436-0",
+      "title": "The Art of Computer Programming"
+    }
+    """
+    Then API response status code is 422 and payload is
+    """json
+    {
+        "code": "INVALID_ISBN",
+        "message": "Unprocessable Entity. Error creating book",
+        "error": "invalid isbn: 9780061964367 is not valid based on external service"
+    }
+    """
+    When API "GET" request is sent to "/internal/metrics" without payload
+    Then API response status code is 200 and payload is
+    """json
+    {
+        "isbn_check_cache": {
+            "hits": 1,
+            "misses": 1
+        }
+    }
+    """
+
+  Scenario: Create a book with a wrong ISBN check digit
     When API "POST" request is sent to "/api/v1/createBook" with payload
     """json
     {
@@ -38,20 +378,356 @@ Feature: Create book
       "title": "The Art of Computer Programming"
     }
     """
+    Then API response status code is 422 and payload is
+    """json
+    {
+        "code": "INVALID_ISBN",
+        "message": "Unprocessable Entity. Error creating book",
+        "error": "invalid isbn: \"0-061-96436-1\" has a wrong ISBN-10 check digit"
+    }
+    """
+
+  Scenario: Create a book that already exists with another ISBN format
+    Given SQL command
+    """
+    INSERT INTO myschema.books (isbn, title, created_at, updated_at)
+    VALUES ('9780061964367', 'The Art of Computer Programming', now(), now());
+    """
+    And a mock server request with method: "GET" and url: "https://api.isbncheck.com/isbn/9780061964367"
+    And a mock server response with status 200 and body
+    """json
+    {
+       "id": "0-061-96436-0"
+    }
+    """
+    When API "POST" request is sent to "/api/v1/createBook" with payload
+    """json
+    {
+      "isbn": "0061964360",
+      "title": "The Art of Computer Programming"
+    }
+    """
+    Then API response status code is 409 and payload is
+    """json
+    {
+        "code": "BOOK_ALREADY_EXISTS",
+        "message": "Conflict. Error creating book",
+        "error": "book already exist"
+    }
+    """
+
+  Scenario: Create a book rejected by the external ISBN service
+    Given a mock server request with method: "GET" and url: "https://api.isbncheck.com/isbn/9780061964367"
+    And a mock server response with status 404 and body
+    """json
+    {}
+    """
+    When API "POST" request is sent to "/api/v1/createBook" with payload
+    """json
+    {
+      "isbn": "0-061-96436-0",
+      "title": "The Art of Computer Programming"
+    }
+    """
+    Then API response status code is 422 and payload is
+    """json
+    {
+        "code": "INVALID_ISBN",
+        "message": "Unprocessable Entity. Error creating book",
+        "error": "invalid isbn: 9780061964367 is not valid based on external service"
+    }
+    """
+    And SQL query "SELECT isbn, valid, expires_at < now() + interval '1 hour' AS short_lived FROM myschema.isbn_check_cache" result is equal to
+    """json
+    [
+       {
+          "isbn":"9780061964367",
+          "valid":false,
+          "short_lived":true
+       }
+    ]
+    """
+
+  Scenario: Create a book when the external ISBN service answers about another ISBN
+    Given a mock server request with method: "GET" and url: "https://api.isbncheck.com/isbn/9780061964367"
+    And a mock server response with status 200 and body
+    """json
+    {
+       "id": "0-201-03801-3"
+    }
+    """
+    When API "POST" request is sent to "/api/v1/createBook" with payload
+    """json
+    {
+      "isbn": "0-061-96436-0",
+      "title": "The Art of Computer Programming"
+    }
+    """
+    Then API response status code is 502 and payload is
+    """json
+    {
+        "code": "UPSTREAM_BAD_RESPONSE",
+        "message": "Bad Gateway. Error creating book",
+        "error": "upstream service bad response: checking isbn: the external service answered \"0-201-03801-3\" for 9780061964367"
+    }
+    """
+
+  Scenario: Create a book when the external ISBN service is down
+    Given a mock server request with method: "GET" and url: "https://api.isbncheck.com/isbn/9780061964367"
+    And a mock server response with status 503 and body
+    """json
+    {}
+    """
+    When API "POST" request is sent to "/api/v1/createBook" with payload
+    """json
+    {
+      "isbn": "0-061-96436-0",
+      "title": "The Art of Computer Programming"
+    }
+    """
+    Then API response status code is 503 and payload is
+    """json
+    {
+        "code": "UPSTREAM_UNAVAILABLE",
+        "message": "Service Unavailable. Error creating book",
+        "error": "upstream service unavailable: checking isbn: status code 503"
+    }
+    """
+    And SQL query "SELECT count(*) AS books FROM myschema.books" result is equal to
+    """json
+    [
+       {
+          "books":0
+       }
+    ]
+    """
+    And SQL query "SELECT count(*) AS cached FROM myschema.isbn_check_cache" result is equal to
+    """json
+    [
+       {
+          "cached":0
+       }
+    ]
+    """
+
+  Scenario: Create a book when the email service fails
+    Given a mock server request with method: "GET" and url: "https://api.isbncheck.com/isbn/9780061964367"
+    And a mock server response with status 200 and body
+    """json
+    {
+       "id": "0-061-96436-0"
+    }
+    """
+    And a mock server request with method: "POST" and url: "https://api.gmail.com/send-email"
+    And a mock server response with status 500 and body
+    """json
+    {}
+    """
+    When API "POST" request is sent to "/api/v1/createBook" with payload
+    """json
+    {
+      "isbn": "0-061-96436-0",
+      "title": "The Art of Computer Programming"
+    }
+    """
     Then API response status code is 200 and payload is
     """json
     {
-        "isbn": "0-061-96436-1",
+        "isbn": "9780061964367",
         "title": "The Art of Computer Programming"
     }
     """
-    And SQL query "SELECT * FROM myschema.books WHERE isbn = '0-061-96436-1'" result without the fields "created_at,deleted_at,updated_at" is equal to
+    And SQL query "SELECT last_error, attempts > 0 AS retried, delivered_at IS NOT NULL AS delivered FROM myschema.outbox_events" result is eventually equal to
+    """json
+    [
+       {
+          "last_error":"upstream service bad response: sending email: status code 500",
+          "retried":true,
+          "delivered":false
+       }
+    ]
+    """
+    Given a mock server request with method: "POST" and url: "https://api.gmail.com/send-email"
+    And a mock server response with status 200 and body
+    """json
+    {
+       "status": "OK"
+    }
+    """
+    Then SQL query "SELECT delivered_at IS NOT NULL AS delivered FROM myschema.outbox_events" result is eventually equal to
+    """json
+    [
+       {
+          "delivered":true
+       }
+    ]
+    """
+    And SQL query "SELECT count(*) AS books FROM myschema.books" result is equal to
+    """json
+    [
+       {
+          "books":1
+       }
+    ]
+    """
+
+  Scenario: Create a book with an invalid payload reports every invalid field
+    When API "POST" request is sent to "/api/v1/createBook" with payload
+    """json
+    {
+      "title": "",
+      "total_pages": 10
+    }
+    """
+    Then API response status code is 422 and payload is
+    """json
+    {
+        "code": "VALIDATION_FAILED",
+        "message": "Unprocessable Entity. Invalid payload",
+        "error": "total_pages: unknown field; title: is required; isbn: is required",
+        "errors": [
+            {
+                "field": "total_pages",
+                "message": "unknown field"
+            },
+            {
+                "field": "title",
+                "message": "is required"
+            },
+            {
+                "field": "isbn",
+                "message": "is required"
+            }
+        ]
+    }
+    """
+
+  Scenario: Create a book with invalid optional fields
+    When API "POST" request is sent to "/api/v1/createBook" with payload
+    """json
+    {
+      "isbn": "0-061-96436-0",
+      "title": "The Art of Computer Programming",
+      "total_pages": -1,
+      "publication_date": "1997-13-01",
+      "language": "english language"
+    }
+    """
+    Then API response status code is 422 and payload is
+    """json
+    {
+        "code": "VALIDATION_FAILED",
+        "message": "Unprocessable Entity. Invalid payload",
+        "error": "total_pages: must be at least 0; publication_date: must be a valid date formatted as YYYY-MM-DD; language: must match the pattern ^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$",
+        "errors": [
+            {
+                "field": "total_pages",
+                "message": "must be at least 0"
+            },
+            {
+                "field": "publication_date",
+                "message": "must be a valid date formatted as YYYY-MM-DD"
+            },
+            {
+                "field": "language",
+                "message": "must match the pattern ^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$"
+            }
+        ]
+    }
+    """
+
+  Scenario: Create a book that references an unknown author
+    Given a mock server request with method: "GET" and url: "https://api.isbncheck.com/isbn/9780061964367"
+    And a mock server response with status 200 and body
+    """json
+    {
+       "id": "0-061-96436-0"
+    }
+    """
+    When API "POST" request is sent to "/api/v1/createBook" with payload
+    """json
+    {
+      "isbn": "0-061-96436-0",
+      "title": "The Art of Computer Programming",
+      "author_ids": [2999]
+    }
+    """
+    Then API response status code is 422 and payload is
+    """json
+    {
+        "code": "UNKNOWN_AUTHOR",
+        "message": "Unprocessable Entity. Error creating book",
+        "error": "unknown author: 2999"
+    }
+    """
+
+  Scenario: Create a book with the deprecated authors field finds or creates the authors by name
+    Given SQL command
+    """
+    INSERT INTO myschema.authors (id, name, created_at, updated_at)
+    VALUES (2001, 'Donald E. Knuth', now(), now());
+    """
+    And a mock server request with method: "GET" and url: "https://api.isbncheck.com/isbn/9780201558029"
+    And a mock server response with status 200 and body
+    """json
+    {
+       "id": "0-201-55802-5"
+    }
+    """
+    And a mock server request with method: "POST" and url: "https://api.gmail.com/send-email" and body
+    """json
+    {
+      "email" : "helloworld@gmail.com",
+      "subject" : "New book: Concrete Mathematics",
+      "body" : "Concrete Mathematics (9780201558029) by Donald E. Knuth, Ronald L. Graham has been added to the catalog.",
+      "book" : {
+        "isbn" : "9780201558029",
+        "title" : "Concrete Mathematics",
+        "authors" : ["Donald E. Knuth", "Ronald L. Graham"]
+      }
+    }
+    """
+    And a mock server response with status 200 and body
+    """json
+    {
+       "status": "OK"
+    }
+    """
+    When API "POST" request is sent to "/api/v1/books" with payload
+    """json
+    {
+      "isbn": "0-201-55802-5",
+      "title": "Concrete Mathematics",
+      "authors": ["Donald E. Knuth", "Ronald L. Graham"]
+    }
+    """
+    Then API response status code is 200 and payload is
+    """json
+    {
+        "isbn": "9780201558029",
+        "title": "Concrete Mathematics",
+        "authors": [
+            {
+                "id": 2001,
+                "name": "Donald E. Knuth"
+            },
+            {
+                "id": 1,
+                "name": "Ronald L. Graham"
+            }
+        ]
+    }
+    """
+    And SQL query "SELECT id, name FROM myschema.authors ORDER BY id" result is equal to
     """json
     [
        {
           "id":1,
-          "isbn":"0-061-96436-1",
-          "title":"The Art of Computer Programming"
+          "name":"Ronald L. Graham"
+       },
+       {
+          "id":2001,
+          "name":"Donald E. Knuth"
        }
     ]
-    """
\ No newline at end of file
+    """
@@ -0,0 +1,44 @@
+Feature: Delete book
+
+  Background: Reset mock server
+    Given reset mock server
+
+  Scenario: Delete an existing book
+    Given SQL command
+    """
+    INSERT INTO myschema.authors (id, name, created_at, updated_at)
+    VALUES (2001, 'Donald E. Knuth', now(), now());
+    INSERT INTO myschema.books (id, isbn, title, created_at, updated_at)
+    VALUES (1001, '9780061964367', 'The Art of Computer Programming', now(), now());
+    INSERT INTO myschema.book_authors (book_id, author_id)
+    VALUES (1001, 2001);
+    """
+    When API "DELETE" request is sent to "/api/v1/books/0-061-96436-0" without payload
+    Then API response status code is 204 without payload
+    And SQL query "SELECT count(*) AS total FROM myschema.books" result is equal to
+    """json
+    [
+       {
+          "total":0
+       }
+    ]
+    """
+    And SQL query "SELECT name FROM myschema.authors" result is equal to
+    """json
+    [
+       {
+          "name":"Donald E. Knuth"
+       }
+    ]
+    """
+
+  Scenario: Delete a book that does not exist
+    When API "DELETE" request is sent to "/api/v1/books/0-061-96436-0" without payload
+    Then API response status code is 404 and payload is
+    """json
+    {
+        "code": "BOOK_NOT_FOUND",
+        "message": "Not Found. Error deleting book",
+        "error": "book not found"
+    }
+    """
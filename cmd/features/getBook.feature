@@ -0,0 +1,80 @@
+Feature: Get book
+
+  Background: Reset mock server
+    Given reset mock server
+
+  Scenario: Get an existing book by ISBN
+    Given SQL command
+    """
+    INSERT INTO myschema.books (isbn, title, created_at, updated_at)
+    VALUES ('9780061964367', 'The Art of Computer Programming', now(), now());
+    """
+    When API "GET" request is sent to "/api/v1/books/0-061-96436-0" without payload
+    Then API response status code is 200 and payload is
+    """json
+    {
+        "isbn": "9780061964367",
+        "title": "The Art of Computer Programming"
+    }
+    """
+
+  Scenario: Get a book that does not exist
+    When API "GET" request is sent to "/api/v1/books/0-061-96436-0" without payload
+    Then API response status code is 404 and payload is
+    """json
+    {
+        "code": "BOOK_NOT_FOUND",
+        "message": "Not Found. Error getting book",
+        "error": "book not found"
+    }
+    """
+
+  Scenario: Get a book with an invalid ISBN
+    When API "GET" request is sent to "/api/v1/books/not-an-isbn" without payload
+    Then API response status code is 422 and payload is
+    """json
+    {
+        "code": "INVALID_ISBN",
+        "message": "Unprocessable Entity. Error getting book",
+        "error": "invalid isbn: \"not-an-isbn\" must have 10 or 13 digits"
+    }
+    """
+
+  Scenario: Get a book that does not exist as problem details
+    Given API request header "Accept" is "application/problem+json"
+    When API "GET" request is sent to "/api/v1/books/0-061-96436-0" without payload
+    Then API response status code is 404 and payload is
+    """json
+    {
+        "type": "/problems/book-not-found",
+        "title": "Book not found",
+        "status": 404,
+        "detail": "Error getting book: book not found",
+        "instance": "/api/v1/books/0-061-96436-0",
+        "code": "BOOK_NOT_FOUND"
+    }
+    """
+    And API response header "Content-Type" is "application/problem+json"
+
+  Scenario: A method that the book does not support is not allowed
+    When API "POST" request is sent to "/api/v1/books/0-061-96436-0" without payload
+    Then API response status code is 405 and payload is
+    """json
+    {
+        "code": "METHOD_NOT_ALLOWED",
+        "message": "Method Not Allowed. DELETE, GET, HEAD, PATCH, PUT required",
+        "error": ""
+    }
+    """
+    And API response header "Allow" is "DELETE, GET, HEAD, PATCH, PUT"
+
+  Scenario: A path without a route is not found
+    When API "GET" request is sent to "/api/v1/unknown" without payload
+    Then API response status code is 404 and payload is
+    """json
+    {
+        "code": "NOT_FOUND",
+        "message": "Not Found. No route for /api/v1/unknown",
+        "error": ""
+    }
+    """
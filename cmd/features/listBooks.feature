@@ -0,0 +1,114 @@
+Feature: List books
+
+  Background: Reset mock server
+    Given reset mock server
+    And SQL command
+    """
+    INSERT INTO myschema.books (id, isbn, title, created_at, updated_at)
+    VALUES (1001, '9780061964367', 'The Art of Computer Programming', '2024-01-01 10:00:00+00', now()),
+           (1002, '9780201038019', 'Fundamental Algorithms', '2024-01-02 10:00:00+00', now()),
+           (1003, '9780201896848', 'Seminumerical Algorithms', '2024-01-03 10:00:00+00', now());
+    """
+
+  Scenario: List the books sorted by creation date by default
+    When API "GET" request is sent to "/api/v1/books" without payload
+    Then API response status code is 200 and payload is
+    """json
+    {
+        "books": [
+            {
+                "isbn": "9780061964367",
+                "title": "The Art of Computer Programming"
+            },
+            {
+                "isbn": "9780201038019",
+                "title": "Fundamental Algorithms"
+            },
+            {
+                "isbn": "9780201896848",
+                "title": "Seminumerical Algorithms"
+            }
+        ],
+        "pagination": {
+            "total_count": 3,
+            "limit": 20,
+            "next_cursor": null
+        }
+    }
+    """
+
+  Scenario: List the first page of books sorted by title
+    When API "GET" request is sent to "/api/v1/books?sort=title&limit=2" without payload
+    Then API response status code is 200 and payload is
+    """json
+    {
+        "books": [
+            {
+                "isbn": "9780201038019",
+                "title": "Fundamental Algorithms"
+            },
+            {
+                "isbn": "9780201896848",
+                "title": "Seminumerical Algorithms"
+            }
+        ],
+        "pagination": {
+            "total_count": 3,
+            "limit": 2,
+            "next_cursor": "eyJ2YWx1ZSI6IlNlbWludW1lcmljYWwgQWxnb3JpdGhtcyIsImlkIjoxMDAzfQ"
+        }
+    }
+    """
+
+  Scenario: List the next page of books with the cursor
+    When API "GET" request is sent to "/api/v1/books?sort=title&limit=2&cursor=eyJ2YWx1ZSI6IlNlbWludW1lcmljYWwgQWxnb3JpdGhtcyIsImlkIjoxMDAzfQ" without payload
+    Then API response status code is 200 and payload is
+    """json
+    {
+        "books": [
+            {
+                "isbn": "9780061964367",
+                "title": "The Art of Computer Programming"
+            }
+        ],
+        "pagination": {
+            "total_count": 3,
+            "limit": 2,
+            "next_cursor": null
+        }
+    }
+    """
+
+  Scenario: List the books filtered by title and ISBN prefix in descending order
+    When API "GET" request is sent to "/api/v1/books?title=algorithms&isbn=978-0-201&sort=-created_at" without payload
+    Then API response status code is 200 and payload is
+    """json
+    {
+        "books": [
+            {
+                "isbn": "9780201896848",
+                "title": "Seminumerical Algorithms"
+            },
+            {
+                "isbn": "9780201038019",
+                "title": "Fundamental Algorithms"
+            }
+        ],
+        "pagination": {
+            "total_count": 2,
+            "limit": 20,
+            "next_cursor": null
+        }
+    }
+    """
+
+  Scenario: List books with an unknown sort field
+    When API "GET" request is sent to "/api/v1/books?sort=pages" without payload
+    Then API response status code is 400 and payload is
+    """json
+    {
+        "code": "INVALID_LIST_QUERY",
+        "message": "Bad Request. Error listing books",
+        "error": "invalid list query: books cannot be sorted by \"pages\""
+    }
+    """
@@ -0,0 +1,124 @@
+Feature: HTTP middlewares
+
+  Background: Reset mock server
+    Given reset mock server
+
+  Scenario: The request ID of the client is echoed
+    Given API request header "X-Request-ID" is "request-0001"
+    When API "GET" request is sent to "/api/v1/books/0-061-96436-0" without payload
+    Then API response status code is 404 and payload is
+    """json
+    {
+        "code": "BOOK_NOT_FOUND",
+        "message": "Not Found. Error getting book",
+        "error": "book not found"
+    }
+    """
+    And API response header "X-Request-ID" is "request-0001"
+
+  Scenario: The request ID is forwarded to the external services
+    Given a mock server request with method: "GET" and url: "https://api.isbncheck.com/isbn/9780061964367"
+    And the mock server request has header "X-Request-ID" with value "request-0002"
+    And a mock server response with status 404 and body
+    """json
+    {}
+    """
+    And API request header "X-Request-ID" is "request-0002"
+    When API "POST" request is sent to "/api/v1/createBook" with payload
+    """json
+    {
+      "isbn": "0-061-96436-0",
+      "title": "The Art of Computer Programming"
+    }
+    """
+    Then API response status code is 422 and payload is
+    """json
+    {
+        "code": "INVALID_ISBN",
+        "message": "Unprocessable Entity. Error creating book",
+        "error": "invalid isbn: 9780061964367 is not valid based on external service"
+    }
+    """
+    And API response header "X-Request-ID" is "request-0002"
+
+  Scenario: A CORS preflight request of an allowed origin
+    Given API request header "Origin" is "https://books.example.com"
+    And API request header "Access-Control-Request-Method" is "POST"
+    When API "OPTIONS" request is sent to "/api/v1/createBook" without payload
+    Then API response status code is 204 without payload
+    And API response header "Access-Control-Allow-Origin" is "https://books.example.com"
+    And API response header "Access-Control-Allow-Methods" is "GET, POST, PUT, PATCH, DELETE"
+    And API response header "Access-Control-Allow-Headers" is "Accept, Content-Type, X-Request-ID"
+
+  Scenario: A request of an origin that is not allowed has no CORS headers
+    Given API request header "Origin" is "https://evil.example.com"
+    When API "GET" request is sent to "/api/v1/books/0-061-96436-0" without payload
+    Then API response status code is 404 and payload is
+    """json
+    {
+        "code": "BOOK_NOT_FOUND",
+        "message": "Not Found. Error getting book",
+        "error": "book not found"
+    }
+    """
+    And API response header "Access-Control-Allow-Origin" is ""
+
+  Scenario: A panicking handler is answered with an internal error
+    Given a mock server request with method: "GET" and url: "https://api.isbncheck.com/isbn/9780061964367"
+    And a mock server response with status 200 and a body that panics when read
+    When API "POST" request is sent to "/api/v1/books" with payload
+    """json
+    {
+      "isbn": "0-061-96436-0",
+      "title": "The Art of Computer Programming"
+    }
+    """
+    Then API response status code is 500 and payload is
+    """json
+    {
+        "code": "INTERNAL_ERROR",
+        "message": "Internal Server Error. Unexpected error",
+        "error": ""
+    }
+    """
+    And SQL query "SELECT count(*) AS books FROM myschema.books" result is equal to
+    """json
+    [
+       {
+          "books":0
+       }
+    ]
+    """
+
+  @short_request_timeout
+  Scenario: A request cut by the timeout of its route is answered with a request timeout
+    Given a mock server request with method: "GET" and url: "https://api.isbncheck.com/isbn/9780061964367"
+    And a mock server response with status 200 after 2000 milliseconds and body
+    """json
+    {
+       "id": "0-061-96436-0"
+    }
+    """
+    When API "POST" request is sent to "/api/v1/books" with payload
+    """json
+    {
+      "isbn": "0-061-96436-0",
+      "title": "The Art of Computer Programming"
+    }
+    """
+    Then API response status code is 503 and payload is
+    """json
+    {
+        "code": "REQUEST_TIMEOUT",
+        "message": "Service Unavailable. Error creating book",
+        "error": "upstream service unavailable: checking isbn: Get \"https://api.isbncheck.com/isbn/9780061964367\": context deadline exceeded"
+    }
+    """
+    And SQL query "SELECT count(*) AS books FROM myschema.books" result is equal to
+    """json
+    [
+       {
+          "books":0
+       }
+    ]
+    """
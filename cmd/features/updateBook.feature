@@ -0,0 +1,145 @@
+Feature: Update book
+
+  Background: Reset mock server
+    Given reset mock server
+
+  Scenario: Replace a book with PUT
+    Given SQL command
+    """
+    INSERT INTO myschema.books (isbn, title, created_at, updated_at)
+    VALUES ('9780061964367', 'The Art of Computer Programming', now(), now());
+    INSERT INTO myschema.authors (id, name, created_at, updated_at)
+    VALUES (2001, 'Donald E. Knuth', now(), now());
+    """
+    When API "PUT" request is sent to "/api/v1/books/0-061-96436-0" with payload
+    """json
+    {
+      "title": "The Art of Computer Programming, Volume 1",
+      "author_ids": [2001],
+      "total_pages": 672
+    }
+    """
+    Then API response status code is 200 and payload is
+    """json
+    {
+        "isbn": "9780061964367",
+        "title": "The Art of Computer Programming, Volume 1",
+        "authors": [
+            {
+                "id": 2001,
+                "name": "Donald E. Knuth"
+            }
+        ],
+        "total_pages": 672
+    }
+    """
+    And SQL query "SELECT isbn, title, total_pages FROM myschema.books WHERE isbn = '9780061964367'" result is equal to
+    """json
+    [
+       {
+          "isbn":"9780061964367",
+          "title":"The Art of Computer Programming, Volume 1",
+          "total_pages":672
+       }
+    ]
+    """
+
+  Scenario: Patch a book keeps the fields that are not sent
+    Given SQL command
+    """
+    INSERT INTO myschema.books (isbn, title, total_pages, created_at, updated_at)
+    VALUES ('9780061964367', 'The Art of Computer Programming', 672, now(), now());
+    """
+    When API "PATCH" request is sent to "/api/v1/books/0-061-96436-0" with payload
+    """json
+    {
+      "publication_date": "1997-07-17"
+    }
+    """
+    Then API response status code is 200 and payload is
+    """json
+    {
+        "isbn": "9780061964367",
+        "title": "The Art of Computer Programming",
+        "total_pages": 672,
+        "publication_date": "1997-07-17"
+    }
+    """
+
+  Scenario: Update a book that does not exist
+    When API "PUT" request is sent to "/api/v1/books/0-061-96436-0" with payload
+    """json
+    {
+      "title": "The Art of Computer Programming"
+    }
+    """
+    Then API response status code is 404 and payload is
+    """json
+    {
+        "code": "BOOK_NOT_FOUND",
+        "message": "Not Found. Error updating book",
+        "error": "book not found"
+    }
+    """
+
+  Scenario: Patch a book with an empty title
+    Given SQL command
+    """
+    INSERT INTO myschema.books (isbn, title, created_at, updated_at)
+    VALUES ('9780061964367', 'The Art of Computer Programming', now(), now());
+    """
+    When API "PATCH" request is sent to "/api/v1/books/0-061-96436-0" with payload
+    """json
+    {
+      "title": ""
+    }
+    """
+    Then API response status code is 422 and payload is
+    """json
+    {
+        "code": "VALIDATION_FAILED",
+        "message": "Unprocessable Entity. Invalid payload",
+        "error": "title: must not be blank",
+        "errors": [
+            {
+                "field": "title",
+                "message": "must not be blank"
+            }
+        ]
+    }
+    """
+
+  Scenario: Patch a book with a blank title
+    Given SQL command
+    """
+    INSERT INTO myschema.books (isbn, title, created_at, updated_at)
+    VALUES ('9780061964367', 'The Art of Computer Programming', now(), now());
+    """
+    When API "PATCH" request is sent to "/api/v1/books/0-061-96436-0" with payload
+    """json
+    {
+      "title": "   "
+    }
+    """
+    Then API response status code is 422 and payload is
+    """json
+    {
+        "code": "VALIDATION_FAILED",
+        "message": "Unprocessable Entity. Invalid payload",
+        "error": "title: must not be blank",
+        "errors": [
+            {
+                "field": "title",
+                "message": "must not be blank"
+            }
+        ]
+    }
+    """
+    And SQL query "SELECT title FROM myschema.books" result is equal to
+    """json
+    [
+       {
+          "title": "The Art of Computer Programming"
+       }
+    ]
+    """
@@ -2,61 +2,48 @@ package main
 
 import (
 	"context"
-	"log"
-	"net/http"
+	"os"
+	"runtime"
+	"strconv"
 	"testing"
-	"time"
 
-	"github.com/cucumber/godog"
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/pkg/gherkintest"
 )
 
 func TestFeatures(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	// Initialize test containers configuration
-	testcontainersConfig := NewMainWithTestContainers(ctx)
-
-	// Channel to notify when the server is ready
-	serverReady := make(chan struct{})
-
-	// Start the HTTP server in a separate goroutine
-	go func() {
-		log.Println("Listening for requests at http://localhost" + testcontainersConfig.Params.MainHttpServerAddress)
-		// Notify that the server is ready
-		close(serverReady)
-
-		if err := testcontainersConfig.MainHttpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Error starting the server: %v", err)
-		}
-	}()
-
-	// Wait for the server to start
-	<-serverReady
-
-	// Allow a brief moment for the server to initialize
-	time.Sleep(500 * time.Millisecond)
-
-	// Run the godog test suite
-	suite := godog.TestSuite{
-		ScenarioInitializer: func(sc *godog.ScenarioContext) {
-			// This address should match the address of the app in the testcontainers_config.go file
-			mainHttpServerUrl := "http://localhost" + testcontainersConfig.Params.MainHttpServerAddress
-			NewStepsContext(mainHttpServerUrl, testcontainersConfig.Database, sc)
-		},
-		Options: &godog.Options{
-			Format:   "pretty",
-			Paths:    []string{"features"}, // Edit this path locally to execute only the feature files you want to test.
-			TestingT: t,                    // Testing instance that will run subtests.
-		},
+	suite, err := gherkintest.New(t).
+		WithPostgres("docker.io/postgres:16-alpine", "db", migrateTestDatabase).
+		WithTruncatedSchemas("myschema").
+		WithMockHost(checkIsbnMockHost, "https://api.isbncheck.com").
+		WithMockHost(emailApiMockHost, "https://api.gmail.com").
+		WithApp(newTestApp).
+		WithSteps(registerMigrationSteps).
+		WithFeaturePaths("features"). // Edit this path locally to execute only the feature files you want to test.
+		WithConcurrency(concurrency(t)).
+		// Serve the apps with an httptest.Server instead of their own http.Server
+		WithHttptestServer(os.Getenv("GODOG_HTTPTEST_SERVER") == "true").
+		Suite(ctx)
+	if err != nil {
+		t.Fatalf("failed to set up the test containers: %v", err)
 	}
 
 	if suite.Run() != 0 {
 		t.Fatal("Non-zero status returned, failed to run feature tests")
 	}
+}
 
-	// Gracefully shutdown the server after tests are done
-	if err := testcontainersConfig.MainHttpServer.Shutdown(ctx); err != nil {
-		log.Fatalf("Error shutting down the server: %v", err)
+// concurrency is the number of scenarios run at the same time, GODOG_CONCURRENCY or the number of CPUs
+func concurrency(t *testing.T) int {
+	value, ok := os.LookupEnv("GODOG_CONCURRENCY")
+	if !ok {
+		return runtime.GOMAXPROCS(0)
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		t.Fatalf("invalid GODOG_CONCURRENCY %q, it must be a positive number", value)
 	}
+	return n
 }
@@ -1,93 +1,332 @@
 package main
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
 	servicebook "github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/application/services/books"
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+	clientsbook "github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/infrastructure/clients/book"
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/infrastructure/clients/resilience"
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/infrastructure/config"
 	controller "github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/infrastructure/controllers"
 	controllerbook "github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/infrastructure/controllers/books"
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/infrastructure/logging"
+	persistancebook "github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/infrastructure/persistance/book"
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/infrastructure/persistance/migrations"
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/infrastructure/requestid"
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/infrastructure/workers"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
 	"gorm.io/gorm/schema"
-	"os"
-	"time"
-
-	"log"
-	"net/http"
-
-	clientsbook "github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/infrastructure/clients/book"
-	persistancebook "github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/infrastructure/persistance/book"
 )
 
 func main() {
-	addr := ":8000"
+	// The level is only known once the configuration is loaded
+	logger := logging.New(os.Stdout, slog.LevelInfo)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrateCommand(context.Background(), logger, os.Args[2:]); err != nil {
+			fatal(logger, "Error running migrate command", err)
+		}
+		return
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		fatal(logger, "Error loading the configuration", err)
+	}
+	level, _ := logging.ParseLevel(cfg.Log.Level) //nolint:errcheck // validated with the configuration
+	logger = logging.New(os.Stdout, level)
+	slog.SetDefault(logger)
+	logger.Info("Configuration loaded", slog.Any("config", cfg))
+	if err := run(cfg, logger); err != nil {
+		fatal(logger, "Error running the server", err)
+	}
+}
+
+// run serves the requests until SIGINT or SIGTERM, then drains the in-flight requests and closes the app
+func run(cfg *config.Config, logger *slog.Logger) error {
+	signalCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	// The app is not cancelled by the signal, so the in-flight requests can finish during the drain
+	appCtx, cancelApp := context.WithCancel(context.Background())
+	defer cancelApp()
 	httpClient := &http.Client{
-		Timeout: 5 * time.Second,
+		Timeout: cfg.HttpClient.Timeout,
 	}
-	server, deferFn := mainHttpServerSetup(addr, httpClient)
-	log.Println("Listing for requests at http://localhost" + addr)
-	err := server.ListenAndServe()
+	server, deferFn, err := mainHttpServerSetup(appCtx, cfg, httpClient, logger)
 	if err != nil {
-		panic("Error staring the server: " + err.Error())
+		return err
 	}
 	defer deferFn()
+	serverErr := make(chan error, 1)
+	go func() {
+		logger.Info("Listening for requests", slog.String("address", cfg.Server.Address))
+		serverErr <- server.ListenAndServe()
+	}()
+	select {
+	case err := <-serverErr:
+		return fmt.Errorf("error starting the server: %w", err)
+	case <-signalCtx.Done():
+	}
+	// A second signal kills the process at once
+	stop()
+	logger.Info("Shutting down, draining the in-flight requests", slog.Duration("timeout", cfg.Server.ShutdownTimeout))
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		// Cancel the requests still running, their queries and their outbound requests
+		cancelApp()
+		server.Close()
+		return fmt.Errorf("error draining the in-flight requests: %w", err)
+	}
+	logger.Info("Server stopped")
+	return nil
+}
+
+// fatal logs the error and exits
+func fatal(logger *slog.Logger, message string, err error) {
+	logger.Error(message, slog.Any("error", err))
+	os.Exit(1)
 }
 
-func mainHttpServerSetup(addr string, httpClient *http.Client) (*http.Server, func()) {
-	db := getDatabaseConnection()
-	// Migrate the schema
-	err := db.AutoMigrate(&persistancebook.BookEntity{})
+// mainHttpServerSetup builds the application. The requests and the background workers are cancelled with ctx.
+func mainHttpServerSetup(ctx context.Context, cfg *config.Config, httpClient *http.Client, logger *slog.Logger) (*http.Server, func(), error) {
+	if err := controllerbook.CheckValidationRules(); err != nil {
+		return nil, nil, fmt.Errorf("error checking the validation rules: %w", err)
+	}
+	// Parsed with the rest of the configuration, before the database and the worker are started
+	routeTimeouts, err := cfg.Server.ParseRouteTimeouts()
+	if err != nil {
+		return nil, nil, err
+	}
+	db, err := getDatabaseConnection(cfg.Database, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	// The pool is closed by deferFn once the app is built, and here on the error returns
+	built := false
+	defer func() {
+		if built {
+			return
+		}
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close() //nolint:errcheck // the error of the setup is more relevant
+		}
+	}()
+	// The schema is owned by the migrations, refuse to start against an outdated one
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting the database connection: %w", err)
+	}
+	migrator, err := newMigrator(sqlDB, logger)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error loading the migrations: %w", err)
+	}
+	if err = migrator.Verify(ctx); err != nil {
+		return nil, nil, fmt.Errorf("error verifying the database migrations: %w", err)
+	}
+	// Clients, sharing the retries and the circuit breakers
+	resilienceOptions := resilience.DefaultOptions
+	resilienceOptions.MaxAttempts = cfg.HttpClient.MaxAttempts
+	resilienceOptions.BaseDelay = cfg.HttpClient.RetryDelay
+	resilienceOptions.FailureThreshold = cfg.HttpClient.BreakerFailures
+	resilienceOptions.OpenTimeout = cfg.HttpClient.BreakerOpenTimeout
+	resilienceOptions.Logger = logger
+	resilientHttpClient := *httpClient
+	// The request ID is set once, every retry forwards it
+	resilientHttpClient.Transport = requestid.NewTransport(resilience.NewTransport(httpClient.Transport, resilienceOptions))
+	httpClient = &resilientHttpClient
+	checkIsbnClient := clientsbook.NewCheckIsbnClient(cfg.CheckIsbn.Host, httpClient)
+	metricsController := controllerbook.NewMetricsController()
+	cachedCheckIsbnClient := newCachedCheckIsbnClient(cfg.IsbnCache, db, checkIsbnClient, metricsController, logger)
+	notificationSettings, err := getNotificationSettings(cfg.Notifications)
+	if err != nil {
+		return nil, nil, err
+	}
+	notificationClient, err := newNotificationClient(cfg.Notifications, httpClient)
 	if err != nil {
-		panic("Error executing db.AutoMigrate" + err.Error())
+		return nil, nil, err
 	}
-	// Clients
-	checkIsbnClientHost := os.Getenv("CHECK_ISBN_CLIENT_HOST")
-	checkIsbnClient := clientsbook.NewCheckIsbnClient(checkIsbnClientHost, httpClient)
-	emailClientHost := os.Getenv("EMAIL_CLIENT_HOST")
-	sendEmailClient := clientsbook.NewSendEmailClient(emailClientHost, httpClient)
 	// repositories
 	newCreateBookRepository := persistancebook.NewCreateBookRepository(db)
+	newGetBookRepository := persistancebook.NewGetBookRepository(db)
+	newListBooksRepository := persistancebook.NewListBooksRepository(db)
+	newUpdateBookRepository := persistancebook.NewUpdateBookRepository(db)
+	newDeleteBookRepository := persistancebook.NewDeleteBookRepository(db)
+	newCreateAuthorRepository := persistancebook.NewCreateAuthorRepository(db)
+	newGetAuthorRepository := persistancebook.NewGetAuthorRepository(db)
+	newListAuthorsRepository := persistancebook.NewListAuthorsRepository(db)
+	newListAuthorBooksRepository := persistancebook.NewListAuthorBooksRepository(db)
+	newDispatchNotificationsRepository := persistancebook.NewDispatchNotificationsRepository(db)
 	// services
-	createBookService := servicebook.NewCreateBookService(newCreateBookRepository, checkIsbnClient, sendEmailClient)
+	createBookService := servicebook.NewCreateBookService(newCreateBookRepository, cachedCheckIsbnClient, notificationSettings, logger)
+	getBookService := servicebook.NewGetBookService(newGetBookRepository)
+	listBooksService := servicebook.NewListBooksService(newListBooksRepository)
+	updateBookService := servicebook.NewUpdateBookService(newUpdateBookRepository)
+	deleteBookService := servicebook.NewDeleteBookService(newDeleteBookRepository)
+	createAuthorService := servicebook.NewCreateAuthorService(newCreateAuthorRepository)
+	getAuthorService := servicebook.NewGetAuthorService(newGetAuthorRepository)
+	listAuthorsService := servicebook.NewListAuthorsService(newListAuthorsRepository)
+	listAuthorBooksService := servicebook.NewListAuthorBooksService(newListAuthorBooksRepository)
+	retryPolicy := servicebook.DefaultRetryPolicy
+	retryPolicy.BaseDelay = cfg.Notifications.OutboxRetryDelay
+	dispatchNotificationsService := servicebook.NewDispatchNotificationsService(newDispatchNotificationsRepository, notificationClient, retryPolicy,
+		cfg.Notifications.OutboxLease, logger)
+	// workers
+	notificationWorker := workers.NewNotificationWorker(dispatchNotificationsService,
+		cfg.Notifications.OutboxPollInterval, 50, logger)
+	notificationWorker.Start(ctx)
 	// controllers
-	bookController := controllerbook.NewBookController(createBookService)
+	bookController := controllerbook.NewBookController(createBookService, getBookService, listBooksService, updateBookService, deleteBookService, logger)
+	authorController := controllerbook.NewAuthorController(createAuthorService, getAuthorService, listAuthorsService, listAuthorBooksService, logger)
 	// routes
-	controller.SetupRoutes(bookController)
+	router := controller.SetupRoutes(controller.RouterOptions{
+		Timeout:       cfg.Server.RequestTimeout,
+		RouteTimeouts: routeTimeouts,
+	}, bookController, authorController, metricsController)
+	// The panics are recovered inside the access log, so their 500 is logged
+	handler := controller.Chain(router,
+		controller.RequestID(),
+		controller.AccessLog(logger),
+		controller.Recovery(logger),
+		controller.CORS(controller.CORSOptions{
+			AllowedOrigins: cfg.CORS.AllowedOrigins,
+			AllowedMethods: cfg.CORS.AllowedMethods,
+			AllowedHeaders: cfg.CORS.AllowedHeaders,
+			MaxAge:         cfg.CORS.MaxAge,
+		}),
+	)
 	// Server
-	server := &http.Server{Addr: addr, Handler: nil}
-	// Defer function
-	// Add all defer
+	server := &http.Server{
+		Addr:              cfg.Server.Address,
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+		// The request contexts derive from ctx, so cancelling it stops the in-flight queries and upstream calls
+		BaseContext: func(net.Listener) context.Context {
+			return ctx
+		},
+		ErrorLog: slog.NewLogLogger(logger.Handler(), slog.LevelError),
+	}
+	// deferFn closes the app once the server is shut down.
+	// The worker uses the clients and the database, so it is stopped first and the database is closed last.
 	deferFn := func() {
-		fmt.Println("closing database connection")
-		sqlDB, err := db.DB()
-		err = sqlDB.Close()
-		if err != nil {
-			panic("Error closing database connection: " + err.Error())
+		logger.Info("Stopping notification worker")
+		notificationWorker.Stop()
+		logger.Info("Closing the HTTP clients")
+		httpClient.CloseIdleConnections()
+		logger.Info("Closing database connection")
+		if err := sqlDB.Close(); err != nil {
+			logger.Error("Error closing database connection", slog.Any("error", err))
+		}
+	}
+	built = true
+	return server, deferFn, nil
+}
+
+// getNotificationSettings maps the configuration to the settings of the services
+func getNotificationSettings(cfg config.NotificationsConfig) (servicebook.NotificationSettings, error) {
+	recipients, err := cfg.NotificationRecipients()
+	if err != nil {
+		return servicebook.NotificationSettings{}, fmt.Errorf("invalid notification recipients: %w", err)
+	}
+	return servicebook.NotificationSettings{
+		Channels:   cfg.Channels,
+		Recipients: recipients,
+	}, nil
+}
+
+// newNotificationClient builds the adapters of the enabled channels
+func newNotificationClient(cfg config.NotificationsConfig, httpClient *http.Client) (*clientsbook.NotificationClient, error) {
+	templates, err := clientsbook.NewNotificationTemplates(cfg.TemplatesDir)
+	if err != nil {
+		return nil, err
+	}
+	notificationChannels := map[string]clientsbook.NotificationChannel{}
+	for _, channel := range cfg.Channels {
+		switch channel {
+		case models.NotificationChannelEmailApi:
+			notificationChannels[channel] = clientsbook.NewSendEmailClient(cfg.EmailApi.Host, httpClient)
+		case models.NotificationChannelSmtp:
+			notificationChannels[channel] = clientsbook.NewSmtpEmailClient(clientsbook.SmtpOptions{
+				Host:     cfg.Smtp.Host,
+				Port:     cfg.Smtp.Port,
+				Username: cfg.Smtp.Username,
+				Password: cfg.Smtp.Password,
+				From:     cfg.Smtp.From,
+				Timeout:  cfg.Smtp.Timeout,
+			})
+		case models.NotificationChannelWebhook:
+			notificationChannels[channel] = clientsbook.NewWebhookClient(httpClient)
 		}
+	}
+	return clientsbook.NewNotificationClient(templates, notificationChannels), nil
+}
 
+// newCachedCheckIsbnClient caches the ISBN checks in the configured store
+func newCachedCheckIsbnClient(cfg config.IsbnCacheConfig, db *gorm.DB, checkIsbnClient *clientsbook.CheckIsbnClient,
+	metricsController *controllerbook.MetricsController, logger *slog.Logger) servicebook.CheckIsbnClientInterface {
+	var cache clientsbook.IsbnCheckCache
+	switch cfg.Store {
+	case config.IsbnCacheMemory:
+		cache = clientsbook.NewMemoryIsbnCheckCache(cfg.MaxEntries)
+	case config.IsbnCachePostgres:
+		cache = persistancebook.NewIsbnCheckCacheRepository(db, cfg.MaxEntries)
+	default:
+		return checkIsbnClient
+	}
+	options := clientsbook.IsbnCheckCacheOptions{
+		PositiveTTL: cfg.TTL,
+		NegativeTTL: cfg.NegativeTTL,
+	}
+	cachedCheckIsbnClient := clientsbook.NewCachedCheckIsbnClient(checkIsbnClient, cache, options, logger)
+	metricsController.Register("isbn_check_cache", func() interface{} {
+		return cachedCheckIsbnClient.Stats()
+	})
+	return cachedCheckIsbnClient
+}
+
+func newMigrator(sqlDB *sql.DB, logger *slog.Logger) (*migrations.Migrator, error) {
+	allMigrations, err := migrations.Migrations()
+	if err != nil {
+		return nil, err
 	}
-	return server, deferFn
+	return migrations.NewMigrator(sqlDB, allMigrations, logger), nil
 }
 
-func getDatabaseConnection() *gorm.DB {
-	// Read database configuration from environment variables
-	databaseUser := os.Getenv("DATABASE_USER")
-	databasePassword := os.Getenv("DATABASE_PASSWORD")
-	databaseName := os.Getenv("DATABASE_NAME")
-	databaseHost := os.Getenv("DATABASE_HOST")
-	databasePort := os.Getenv("DATABASE_PORT")
-	databaseConnectionString := `host=` + databaseHost + ` user=` + databaseUser + ` password=` + databasePassword + ` dbname=` + databaseName + ` port=` + databasePort + ` sslmode=disable`
-	fmt.Println("databaseConnectionString: ", databaseConnectionString)
+// getDatabaseConnection opens the database. The password is never part of the errors nor the logs.
+func getDatabaseConnection(cfg config.DatabaseConfig, logger *slog.Logger) (*gorm.DB, error) {
 	db, err := gorm.Open(postgres.New(postgres.Config{
-		DSN:                  databaseConnectionString,
+		DSN:                  cfg.DSN(),
 		PreferSimpleProtocol: true, // disables implicit prepared statement usage
 	}), &gorm.Config{
 		NamingStrategy: schema.NamingStrategy{
 			TablePrefix:   "myschema.", // schema name
 			SingularTable: false,
-		}})
+		},
+		// The values of the queries are not logged, they may be personal data
+		Logger: gormlogger.New(logging.PrintfWriter{Logger: logger, Level: slog.LevelWarn}, gormlogger.Config{
+			SlowThreshold:             200 * time.Millisecond,
+			LogLevel:                  gormlogger.Warn,
+			IgnoreRecordNotFoundError: true,
+			ParameterizedQueries:      true,
+		}),
+	})
 	// Check if connection is successful
 	if err != nil {
-		panic("failed to connect database with error: " + err.Error() + "\n" + "Please check your database configuration: " + databaseConnectionString)
+		return nil, fmt.Errorf("failed to connect to the database host=%s port=%d dbname=%s user=%s: %s",
+			cfg.Host, cfg.Port, cfg.Name, cfg.User, logging.RedactText(err.Error()))
 	}
-	return db
+	return db, nil
 }
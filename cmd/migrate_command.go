@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/infrastructure/config"
+)
+
+const migrateUsage = "usage: migrate up | down [steps] | status"
+
+// runMigrateCommand runs the migrate subcommand with its arguments, e.g. ["down", "1"]
+func runMigrateCommand(ctx context.Context, logger *slog.Logger, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+	// Only the database is needed, so the rest of the configuration is not validated
+	cfg, err := config.Read(os.Getenv(config.FileEnvVar), os.LookupEnv)
+	if err != nil {
+		return err
+	}
+	if err := cfg.Database.Validate(); err != nil {
+		return err
+	}
+	db, err := getDatabaseConnection(cfg.Database, logger)
+	if err != nil {
+		return err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	defer sqlDB.Close()
+	migrator, err := newMigrator(sqlDB, logger)
+	if err != nil {
+		return err
+	}
+	switch args[0] {
+	case "up":
+		return migrator.Up(ctx)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("invalid steps %q, it must be a positive number. %s", args[1], migrateUsage)
+			}
+		}
+		return migrator.Down(ctx, steps)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "MIGRATION\tAPPLIED AT")
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05 MST")
+			}
+			fmt.Fprintf(writer, "%s\t%s\n", status.Migration, appliedAt)
+		}
+		return writer.Flush()
+	default:
+		return fmt.Errorf("unknown migrate command %q. %s", args[0], migrateUsage)
+	}
+}
@@ -1 +0,0 @@
-create schema myschema;
\ No newline at end of file
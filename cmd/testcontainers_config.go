@@ -3,146 +3,115 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
-	"github.com/jarcoal/httpmock"
-	"github.com/testcontainers/testcontainers-go"
-	"github.com/testcontainers/testcontainers-go/modules/postgres"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
-	"path/filepath"
+	"slices"
 	"time"
 
-	"github.com/docker/go-connections/nat"
+	"github.com/cucumber/godog"
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/infrastructure/config"
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/infrastructure/logging"
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/infrastructure/persistance/migrations"
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/pkg/gherkintest"
+)
 
-	_ "github.com/lib/pq" // Import the postgres driver
-	"github.com/testcontainers/testcontainers-go/wait"
+// The names of the mocked services, see gherkintest.Builder.WithMockHost
+const (
+	checkIsbnMockHost = "check_isbn"
+	emailApiMockHost  = "email_api"
 )
 
-type TestContainersParams struct {
-	MainHttpServerAddress  string
-	PostgresImage          string
-	DatabaseName           string
-	DatabaseHostEnvVar     string
-	DatabasePortEnvVar     string
-	DatabaseUserEnvVar     string
-	DatabasePasswordEnvVar string
-	DatabaseNameEnvVar     string
-	DatabaseInitScript     string
-	EnvironmentVariables   map[string]string
-}
+// The tags of the scenarios that change the configuration of their app
+const (
+	// isbnCacheMemoryTag caches the ISBN checks in memory instead of in Postgres
+	isbnCacheMemoryTag = "@isbn_cache_memory"
+	// shortRequestTimeoutTag gives 200ms to POST /api/v1/books, so a slow mock server response cuts the request
+	shortRequestTimeoutTag = "@short_request_timeout"
+)
 
-type TestContainersContext struct {
-	MainHttpServer *http.Server
-	Database       *sql.DB
-	Params         *TestContainersParams
+// NewTestConfig returns the configuration of the app under test, without the database and the hosts of the mocked
+// services, which are set per scenario
+func NewTestConfig() *config.Config {
+	cfg := config.Default()
+	// Dispatch and retry the notifications quickly so the scenarios do not wait for them
+	cfg.Notifications.OutboxPollInterval = 100 * time.Millisecond
+	cfg.Notifications.OutboxRetryDelay = 100 * time.Millisecond
+	// The webhook subscribers are added by the scenarios
+	cfg.Notifications.Channels = []string{"email_api", "webhook"}
+	cfg.Notifications.Recipients = []string{"email_api:helloworld@gmail.com"}
+	// Keep the retries short
+	cfg.HttpClient.RetryDelay = 10 * time.Millisecond
+	cfg.HttpClient.BreakerOpenTimeout = 100 * time.Millisecond
+	// The origin of the CORS scenarios
+	cfg.CORS.AllowedOrigins = []string{"https://books.example.com"}
+	// The cache table is cleaned with the other tables before every scenario
+	cfg.IsbnCache.Store = config.IsbnCachePostgres
+	return cfg
 }
 
-func NewTestContainersParams() *TestContainersParams {
-	return &TestContainersParams{
-		MainHttpServerAddress:  ":8000",
-		PostgresImage:          "docker.io/postgres:16-alpine",
-		DatabaseName:           "db",
-		DatabaseHostEnvVar:     "DATABASE_HOST",
-		DatabasePortEnvVar:     "DATABASE_PORT",
-		DatabaseUserEnvVar:     "DATABASE_USER",
-		DatabasePasswordEnvVar: "DATABASE_PASSWORD",
-		DatabaseNameEnvVar:     "DATABASE_NAME",
-		DatabaseInitScript:     filepath.Join(".", "testAssets", "testdata", "dev-db.sql"),
-		EnvironmentVariables: map[string]string{
-			"CHECK_ISBN_CLIENT_HOST": "https://api.isbncheck.com",
-			"EMAIL_CLIENT_HOST":      "https://api.gmail.com",
-		},
+// newTestApp builds the app of a scenario, see gherkintest.AppFactory
+func newTestApp(ctx context.Context, env gherkintest.AppEnv) (*http.Server, func(), error) {
+	cfg := NewTestConfig()
+	cfg.CheckIsbn.Host = env.MockHosts[checkIsbnMockHost]
+	cfg.Notifications.EmailApi.Host = env.MockHosts[emailApiMockHost]
+	cfg.Database.Host = env.Database.Host
+	cfg.Database.Port = env.Database.Port
+	cfg.Database.User = env.Database.User
+	cfg.Database.Password = env.Database.Password
+	cfg.Database.Name = env.Database.Name
+	if slices.Contains(env.Tags, isbnCacheMemoryTag) {
+		cfg.IsbnCache.Store = config.IsbnCacheMemory
 	}
-}
-
-func NewMainWithTestContainers(ctx context.Context) *TestContainersContext {
-	params := NewTestContainersParams()
-	// Set the environment variables
-	setEnvVars(params.EnvironmentVariables)
-	// Start the postgres container
-	initPostgresContainer(ctx, params)
-	// Create database connection
-	db := getDatabaseConnectionTestContainers(params)
-	// Mock the third-party API client
-	mockClient := &http.Client{}
-	httpmock.ActivateNonDefault(mockClient)
-	// Build the app
-	server, _ := mainHttpServerSetup(params.MainHttpServerAddress, mockClient)
-	return &TestContainersContext{
-		MainHttpServer: server,
-		Database:       db,
-		Params:         params,
+	if slices.Contains(env.Tags, shortRequestTimeoutTag) {
+		cfg.Server.RouteTimeouts = []string{"POST /api/v1/books=200ms"}
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("invalid configuration: %w", err)
 	}
+	level, _ := logging.ParseLevel(cfg.Log.Level) //nolint:errcheck // validated with the configuration
+	return mainHttpServerSetup(ctx, cfg, env.HttpClient, logging.New(os.Stdout, level).With("scenario", env.Name))
 }
 
-func getDatabaseConnectionTestContainers(params *TestContainersParams) *sql.DB {
-	db, err := sql.Open("postgres", fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-		os.Getenv(params.DatabaseHostEnvVar),
-		os.Getenv(params.DatabasePortEnvVar),
-		os.Getenv(params.DatabaseUserEnvVar),
-		os.Getenv(params.DatabasePasswordEnvVar),
-		os.Getenv(params.DatabaseNameEnvVar),
-	))
+// migrateTestDatabase creates the schema with the same migrations as production
+func migrateTestDatabase(ctx context.Context, db *sql.DB) error {
+	allMigrations, err := migrations.Migrations()
 	if err != nil {
-		log.Fatalf("failed to connect to database: %s", err)
+		return fmt.Errorf("failed to load the migrations: %w", err)
 	}
-	return db
+	return migrations.NewMigrator(db, allMigrations, slog.Default()).Up(ctx)
 }
 
-// initPostgresContainer starts a postgres container and sets the required environment variables.
-// Source: https://golang.testcontainers.org/modules/postgres/
-func initPostgresContainer(ctx context.Context, params *TestContainersParams) *postgres.PostgresContainer {
-	logTimeout := 10
-	port := "5432/tcp"
-	dbURL := func(host string, port nat.Port) string {
-		return fmt.Sprintf("postgres://postgres:postgres@%s:%s/%s?sslmode=disable",
-			host, port.Port(), params.DatabaseName)
-	}
-
-	postgresContainer, err := postgres.Run(ctx, params.PostgresImage,
-		postgres.WithInitScripts(params.DatabaseInitScript),
-		postgres.WithDatabase(params.DatabaseName),
-		testcontainers.WithWaitStrategy(wait.ForSQL(nat.Port(port), "postgres", dbURL).
-			WithStartupTimeout(time.Duration(logTimeout)*time.Second)),
-	)
-	if err != nil {
-		log.Fatalf("failed to start postgresContainer: %s", err)
-	}
-	postgresHost, _ := postgresContainer.Host(ctx) //nolint:errcheck // non-critical
-	// Set database environment variables
-	setEnvVars(map[string]string{
-		params.DatabaseHostEnvVar:     postgresHost,
-		params.DatabasePortEnvVar:     getPostgresPort(ctx, postgresContainer),
-		params.DatabaseUserEnvVar:     "postgres",
-		params.DatabasePasswordEnvVar: "postgres",
-		params.DatabaseNameEnvVar:     params.DatabaseName,
+// registerMigrationSteps registers the steps upgrading the database of the scenario, see gherkintest.Builder.WithSteps
+func registerMigrationSteps(sc *godog.ScenarioContext, s *gherkintest.StepsContext) {
+	sc.Step(`^the migrations after version (\d+) are applied again after SQL command$`, func(ctx context.Context, version int64, sqlCommand string) error {
+		return reapplyMigrations(ctx, s.Database(), version, sqlCommand)
 	})
-
-	s, err := postgresContainer.ConnectionString(ctx)
-	log.Printf("Postgres container started at: %s", s)
-	if err != nil {
-		log.Fatalf("error from initPostgresContainer - ConnectionString: %e", err)
-	}
-
-	return postgresContainer
-
 }
 
-func getPostgresPort(ctx context.Context, postgresContainer *postgres.PostgresContainer) string {
-	port, e := postgresContainer.MappedPort(ctx, "5432/tcp")
-	if e != nil {
-		log.Fatalf("Failed to get postgresContainer.Ports: %s", e)
+// reapplyMigrations reverts the migrations after version, runs sqlCommand on the reverted schema, e.g. to seed the data
+// of an older version, and applies the migrations again. They are applied again also when sqlCommand fails, so the
+// database is migrated for the next scenarios.
+func reapplyMigrations(ctx context.Context, db *sql.DB, version int64, sqlCommand string) error {
+	allMigrations, err := migrations.Migrations()
+	if err != nil {
+		return fmt.Errorf("failed to load the migrations: %w", err)
 	}
-
-	return port.Port()
-}
-
-// setEnvVars sets environment variables from a map.
-func setEnvVars(envs map[string]string) {
-	for key, value := range envs {
-		if err := os.Setenv(key, value); err != nil {
-			log.Fatalf("Failed to set environment variable %s: %v", key, err)
+	steps := 0
+	for _, migration := range allMigrations {
+		if migration.Version > version {
+			steps++
 		}
 	}
+	migrator := migrations.NewMigrator(db, allMigrations, slog.Default())
+	if err := migrator.Down(ctx, steps); err != nil {
+		return fmt.Errorf("failed to revert the migrations: %w", err)
+	}
+	_, execErr := db.ExecContext(ctx, sqlCommand)
+	if err := migrator.Up(ctx); err != nil {
+		return errors.Join(execErr, fmt.Errorf("failed to apply the migrations: %w", err))
+	}
+	return execErr
 }
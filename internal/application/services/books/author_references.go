@@ -0,0 +1,65 @@
+package books
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+)
+
+// AuthorReferencesRepositoryInterface Outbound port
+type AuthorReferencesRepositoryInterface interface {
+	// SelectAuthorsByIds returns the authors found, in any order
+	SelectAuthorsByIds(ctx context.Context, ids []uint) ([]models.Author, error)
+}
+
+// resolveAuthors loads the authors referenced by ID in the same order, or fails with ErrUnknownAuthor.
+// The authors referenced by name, from the deprecated authors field of the payloads, are kept as references: the
+// repository finds or creates them in the transaction writing the book, so a failed write leaves no new author behind.
+func resolveAuthors(ctx context.Context, repository AuthorReferencesRepositoryInterface, references []models.Author) ([]models.Author, error) {
+	if len(references) == 0 {
+		return []models.Author{}, nil
+	}
+	var ids []uint
+	for _, reference := range references {
+		if !isNameReference(reference) {
+			ids = append(ids, reference.ID)
+		}
+	}
+	authorsById := make(map[uint]models.Author, len(ids))
+	if len(ids) > 0 {
+		found, err := repository.SelectAuthorsByIds(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, author := range found {
+			authorsById[author.ID] = author
+		}
+	}
+	authors := make([]models.Author, 0, len(references))
+	seenIds := make(map[uint]bool, len(references))
+	seenNames := make(map[string]bool, len(references))
+	for _, reference := range references {
+		if isNameReference(reference) {
+			if !seenNames[reference.Name] {
+				seenNames[reference.Name] = true
+				authors = append(authors, reference)
+			}
+			continue
+		}
+		author, ok := authorsById[reference.ID]
+		if !ok {
+			return nil, fmt.Errorf("%w: %d", ErrUnknownAuthor, reference.ID)
+		}
+		if !seenIds[author.ID] {
+			seenIds[author.ID] = true
+			authors = append(authors, author)
+		}
+	}
+	return authors, nil
+}
+
+// isNameReference tells whether an author is referenced by name instead of ID
+func isNameReference(reference models.Author) bool {
+	return reference.ID == 0 && reference.Name != ""
+}
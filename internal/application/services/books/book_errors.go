@@ -0,0 +1,27 @@
+package books
+
+import (
+	"errors"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+)
+
+// The services return errors wrapping one of these, so the callers can tell the failures apart with errors.Is
+var (
+	// ErrBookNotFound is returned when no book matches the requested ISBN
+	ErrBookNotFound = errors.New("book not found")
+	// ErrBookAlreadyExists is returned when creating a book whose ISBN is already stored
+	ErrBookAlreadyExists = errors.New("book already exist")
+	// ErrAuthorNotFound is returned when no author matches the requested ID
+	ErrAuthorNotFound = errors.New("author not found")
+	// ErrUnknownAuthor is returned when a book references an author that does not exist
+	ErrUnknownAuthor = errors.New("unknown author")
+	// ErrInvalidIsbn is returned when the ISBN is malformed or rejected by the external service
+	ErrInvalidIsbn = models.ErrInvalidIsbn
+	// ErrInvalidListQuery is returned when the filters, sorting or pagination of a listing are not valid
+	ErrInvalidListQuery = errors.New("invalid list query")
+	// ErrUpstreamUnavailable is returned when an external service cannot be reached
+	ErrUpstreamUnavailable = errors.New("upstream service unavailable")
+	// ErrUpstreamBadResponse is returned when an external service answers with an unexpected response
+	ErrUpstreamBadResponse = errors.New("upstream service bad response")
+)
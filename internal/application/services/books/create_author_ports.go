@@ -0,0 +1,17 @@
+package books
+
+import (
+	"context"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+)
+
+// CreateAuthorServiceInterface Inbound port
+type CreateAuthorServiceInterface interface {
+	CreateAuthor(ctx context.Context, author *models.Author) (*models.Author, error)
+}
+
+// CreateAuthorRepositoryInterface Outbound port
+type CreateAuthorRepositoryInterface interface {
+	InsertAuthor(ctx context.Context, author *models.Author) (*models.Author, error)
+}
@@ -0,0 +1,21 @@
+package books
+
+import (
+	"context"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+)
+
+type CreateAuthorService struct {
+	repository CreateAuthorRepositoryInterface
+}
+
+func NewCreateAuthorService(repository CreateAuthorRepositoryInterface) *CreateAuthorService {
+	return &CreateAuthorService{
+		repository: repository,
+	}
+}
+
+func (s *CreateAuthorService) CreateAuthor(ctx context.Context, author *models.Author) (*models.Author, error) {
+	return s.repository.InsertAuthor(ctx, author)
+}
@@ -1,24 +1,26 @@
 package books
 
-import "github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+import (
+	"context"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+)
 
 // CreateBookServiceInterface Inbound port
 type CreateBookServiceInterface interface {
-	CreateBook(book *models.Book) (*models.Book, error)
+	CreateBook(ctx context.Context, book *models.Book) (*models.Book, error)
 }
 
 // CreateBookRepositoryInterface Outbound port
 type CreateBookRepositoryInterface interface {
-	SelectBookByIsbn(isbn string) (*models.Book, error)
-	InsertBook(book *models.Book) (*models.Book, error)
+	AuthorReferencesRepositoryInterface
+	NotificationSubscribersRepositoryInterface
+	SelectBookByIsbn(ctx context.Context, isbn string) (*models.Book, error)
+	// InsertBook stores the book and its notifications in the outbox in the same transaction
+	InsertBook(ctx context.Context, book *models.Book, notifications []models.Notification) (*models.Book, error)
 }
 
 // CheckIsbnClientInterface Outbound port
 type CheckIsbnClientInterface interface {
-	CheckIsbn(isbn string) (bool, error)
-}
-
-// SendEmailClient Outbound port
-type SendEmailClientInterface interface {
-	SendEmail(email string, book *models.Book) error
+	CheckIsbn(ctx context.Context, isbn string) (*models.IsbnCheck, error)
 }
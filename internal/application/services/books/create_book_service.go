@@ -1,7 +1,9 @@
 package books
 
 import (
-	"errors"
+	"context"
+	"fmt"
+	"log/slog"
 
 	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
 )
@@ -9,44 +11,78 @@ import (
 type CreateBookService struct {
 	repository               CreateBookRepositoryInterface
 	checkIsbnClientInterface CheckIsbnClientInterface
-	sendEmailClientInterface SendEmailClientInterface
+	notificationSettings     NotificationSettings
+	logger                   *slog.Logger
 }
 
 func NewCreateBookService(repository CreateBookRepositoryInterface,
 	checkIsbnClientInterface CheckIsbnClientInterface,
-	sendEmailClientInterface SendEmailClientInterface) *CreateBookService {
+	notificationSettings NotificationSettings,
+	logger *slog.Logger) *CreateBookService {
 	return &CreateBookService{
 		repository:               repository,
 		checkIsbnClientInterface: checkIsbnClientInterface,
-		sendEmailClientInterface: sendEmailClientInterface,
+		notificationSettings:     notificationSettings,
+		logger:                   logger,
 	}
 }
 
-func (s *CreateBookService) CreateBook(book *models.Book) (*models.Book, error) {
+func (s *CreateBookService) CreateBook(ctx context.Context, book *models.Book) (*models.Book, error) {
+	// Check the ISBN locally before calling the external service
+	isbn, err := models.NormalizeIsbn(book.Isbn)
+	if err != nil {
+		return nil, err
+	}
+	normalizedBook := *book
+	normalizedBook.Isbn = isbn
+	book = &normalizedBook
 	// Check if ISBN is valid
-	isValid, err := s.checkIsbnClientInterface.CheckIsbn(book.Isbn)
+	isbnCheck, err := s.checkIsbnClientInterface.CheckIsbn(ctx, book.Isbn)
 	if err != nil {
 		return nil, err
 	}
-	if !isValid {
-		return nil, errors.New("isbn is not valid based on external service")
+	if !isbnCheck.Valid {
+		return nil, fmt.Errorf("%w: %s is not valid based on external service", ErrInvalidIsbn, book.Isbn)
+	}
+	if err = crossCheckIsbn(book, isbnCheck); err != nil {
+		return nil, err
 	}
 	// Check if book already exist
-	exist, err := s.repository.SelectBookByIsbn(book.Isbn)
+	exist, err := s.repository.SelectBookByIsbn(ctx, book.Isbn)
 	if err != nil {
 		return nil, err
 	}
 	if exist != nil {
-		return nil, errors.New("book already exist")
+		return nil, ErrBookAlreadyExists
+	}
+	// Check if the authors exist
+	book.Authors, err = resolveAuthors(ctx, s.repository, book.Authors)
+	if err != nil {
+		return nil, err
 	}
-	storedBook, err := s.repository.InsertBook(book)
+	// The notifications are sent later by the outbox dispatcher, so an outage of a channel does not fail the creation
+	notifications, err := newNotifications(ctx, s.repository, s.notificationSettings, models.NotificationEventBookCreated, book)
 	if err != nil {
 		return nil, err
 	}
-	// Send email
-	err = s.sendEmailClientInterface.SendEmail("helloworld@gmail.com", storedBook)
+	createdBook, err := s.repository.InsertBook(ctx, book, notifications)
 	if err != nil {
 		return nil, err
 	}
-	return storedBook, nil
+	s.logger.InfoContext(ctx, "Book created", slog.String("isbn", createdBook.Isbn),
+		slog.Int("notifications", len(notifications)))
+	return createdBook, nil
+}
+
+// crossCheckIsbn verifies that the external service answered about the requested book.
+// The canonical id is optional, but when it is sent it must be the same ISBN in any format.
+func crossCheckIsbn(book *models.Book, isbnCheck *models.IsbnCheck) error {
+	if isbnCheck.CanonicalId == "" {
+		return nil
+	}
+	canonicalIsbn, err := models.NormalizeIsbn(isbnCheck.CanonicalId)
+	if err != nil || canonicalIsbn != book.Isbn {
+		return fmt.Errorf("%w: checking isbn: the external service answered %q for %s", ErrUpstreamBadResponse, isbnCheck.CanonicalId, book.Isbn)
+	}
+	return nil
 }
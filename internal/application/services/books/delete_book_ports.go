@@ -0,0 +1,14 @@
+package books
+
+import "context"
+
+// DeleteBookServiceInterface Inbound port
+type DeleteBookServiceInterface interface {
+	DeleteBook(ctx context.Context, isbn string) error
+}
+
+// DeleteBookRepositoryInterface Outbound port
+type DeleteBookRepositoryInterface interface {
+	// DeleteBookByIsbn returns false when there was no book to delete
+	DeleteBookByIsbn(ctx context.Context, isbn string) (bool, error)
+}
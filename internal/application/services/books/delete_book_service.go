@@ -0,0 +1,32 @@
+package books
+
+import (
+	"context"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+)
+
+type DeleteBookService struct {
+	repository DeleteBookRepositoryInterface
+}
+
+func NewDeleteBookService(repository DeleteBookRepositoryInterface) *DeleteBookService {
+	return &DeleteBookService{
+		repository: repository,
+	}
+}
+
+func (s *DeleteBookService) DeleteBook(ctx context.Context, isbn string) error {
+	isbn, err := models.NormalizeIsbn(isbn)
+	if err != nil {
+		return err
+	}
+	deleted, err := s.repository.DeleteBookByIsbn(ctx, isbn)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrBookNotFound
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package books
+
+import (
+	"context"
+	"time"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+)
+
+// DispatchNotificationsServiceInterface Inbound port
+type DispatchNotificationsServiceInterface interface {
+	DispatchNotifications(ctx context.Context, limit int) (int, error)
+}
+
+// DispatchNotificationsRepositoryInterface Outbound port
+type DispatchNotificationsRepositoryInterface interface {
+	// ClaimNotifications locks up to limit due notifications for the lease, so other dispatchers skip them
+	ClaimNotifications(ctx context.Context, limit int, lease time.Duration) ([]models.Notification, error)
+	MarkNotificationDelivered(ctx context.Context, id uint) error
+	// MarkNotificationFailed records a failed attempt, nextAttemptAt is nil when there are no retries left
+	MarkNotificationFailed(ctx context.Context, id uint, lastError string, nextAttemptAt *time.Time) error
+}
+
+// SendNotificationClientInterface Outbound port
+type SendNotificationClientInterface interface {
+	SendNotification(ctx context.Context, notification *models.Notification) error
+}
@@ -0,0 +1,99 @@
+package books
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+)
+
+// RetryPolicy is the exponential backoff between the delivery attempts of a notification
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// DefaultRetryPolicy retries for about half an hour
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 10,
+	BaseDelay:   time.Second,
+	MaxDelay:    5 * time.Minute,
+}
+
+// Delay returns how long to wait after the given number of failed attempts
+func (p RetryPolicy) Delay(attempts int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempts && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, p.MaxDelay)
+}
+
+type DispatchNotificationsService struct {
+	repository                      DispatchNotificationsRepositoryInterface
+	sendNotificationClientInterface SendNotificationClientInterface
+	retryPolicy                     RetryPolicy
+	// lease is how long a claimed notification is hidden from the other dispatchers.
+	// It must be longer than sending a notification, otherwise it may be sent twice.
+	lease  time.Duration
+	logger *slog.Logger
+}
+
+func NewDispatchNotificationsService(repository DispatchNotificationsRepositoryInterface,
+	sendNotificationClientInterface SendNotificationClientInterface,
+	retryPolicy RetryPolicy,
+	lease time.Duration,
+	logger *slog.Logger) *DispatchNotificationsService {
+	return &DispatchNotificationsService{
+		repository:                      repository,
+		sendNotificationClientInterface: sendNotificationClientInterface,
+		retryPolicy:                     retryPolicy,
+		lease:                           lease,
+		logger:                          logger,
+	}
+}
+
+// DispatchNotifications sends up to limit due notifications and returns how many were processed,
+// delivered or not. A failed delivery is scheduled again until the retry policy gives up.
+// The notifications are claimed one at a time right before being sent, so the lease only has to outlast a single send.
+func (s *DispatchNotificationsService) DispatchNotifications(ctx context.Context, limit int) (int, error) {
+	for processed := 0; processed < limit; processed++ {
+		notifications, err := s.repository.ClaimNotifications(ctx, 1, s.lease)
+		if err != nil {
+			return processed, err
+		}
+		if len(notifications) == 0 {
+			return processed, nil
+		}
+		if err := s.dispatchNotification(ctx, &notifications[0]); err != nil {
+			return processed, err
+		}
+	}
+	return limit, nil
+}
+
+// dispatchNotification sends a claimed notification and records the outcome
+func (s *DispatchNotificationsService) dispatchNotification(ctx context.Context, notification *models.Notification) error {
+	sendErr := s.sendNotificationClientInterface.SendNotification(ctx, notification)
+	logger := s.logger.With(slog.Uint64("notification_id", uint64(notification.ID)),
+		slog.String("channel", notification.Recipient.Channel))
+	if sendErr == nil {
+		logger.DebugContext(ctx, "Notification delivered")
+		return s.repository.MarkNotificationDelivered(ctx, notification.ID)
+	}
+	attempts := notification.Attempts + 1
+	var nextAttemptAt *time.Time
+	if attempts < s.retryPolicy.MaxAttempts {
+		next := time.Now().Add(s.retryPolicy.Delay(attempts))
+		nextAttemptAt = &next
+	}
+	level := slog.LevelWarn
+	if nextAttemptAt == nil {
+		level = slog.LevelError
+	}
+	logger.Log(ctx, level, "Error sending notification", slog.Int("attempt", attempts),
+		slog.Int("max_attempts", s.retryPolicy.MaxAttempts), slog.Any("error", sendErr))
+	return s.repository.MarkNotificationFailed(ctx, notification.ID, sendErr.Error(), nextAttemptAt)
+}
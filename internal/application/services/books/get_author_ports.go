@@ -0,0 +1,17 @@
+package books
+
+import (
+	"context"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+)
+
+// GetAuthorServiceInterface Inbound port
+type GetAuthorServiceInterface interface {
+	GetAuthor(ctx context.Context, id uint) (*models.Author, error)
+}
+
+// GetAuthorRepositoryInterface Outbound port
+type GetAuthorRepositoryInterface interface {
+	SelectAuthorById(ctx context.Context, id uint) (*models.Author, error)
+}
@@ -0,0 +1,28 @@
+package books
+
+import (
+	"context"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+)
+
+type GetAuthorService struct {
+	repository GetAuthorRepositoryInterface
+}
+
+func NewGetAuthorService(repository GetAuthorRepositoryInterface) *GetAuthorService {
+	return &GetAuthorService{
+		repository: repository,
+	}
+}
+
+func (s *GetAuthorService) GetAuthor(ctx context.Context, id uint) (*models.Author, error) {
+	author, err := s.repository.SelectAuthorById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if author == nil {
+		return nil, ErrAuthorNotFound
+	}
+	return author, nil
+}
@@ -0,0 +1,17 @@
+package books
+
+import (
+	"context"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+)
+
+// GetBookServiceInterface Inbound port
+type GetBookServiceInterface interface {
+	GetBook(ctx context.Context, isbn string) (*models.Book, error)
+}
+
+// GetBookRepositoryInterface Outbound port
+type GetBookRepositoryInterface interface {
+	SelectBookByIsbn(ctx context.Context, isbn string) (*models.Book, error)
+}
@@ -0,0 +1,32 @@
+package books
+
+import (
+	"context"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+)
+
+type GetBookService struct {
+	repository GetBookRepositoryInterface
+}
+
+func NewGetBookService(repository GetBookRepositoryInterface) *GetBookService {
+	return &GetBookService{
+		repository: repository,
+	}
+}
+
+func (s *GetBookService) GetBook(ctx context.Context, isbn string) (*models.Book, error) {
+	isbn, err := models.NormalizeIsbn(isbn)
+	if err != nil {
+		return nil, err
+	}
+	book, err := s.repository.SelectBookByIsbn(ctx, isbn)
+	if err != nil {
+		return nil, err
+	}
+	if book == nil {
+		return nil, ErrBookNotFound
+	}
+	return book, nil
+}
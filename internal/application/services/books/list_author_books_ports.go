@@ -0,0 +1,18 @@
+package books
+
+import (
+	"context"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+)
+
+// ListAuthorBooksServiceInterface Inbound port
+type ListAuthorBooksServiceInterface interface {
+	ListAuthorBooks(ctx context.Context, authorId uint) ([]*models.Book, error)
+}
+
+// ListAuthorBooksRepositoryInterface Outbound port
+type ListAuthorBooksRepositoryInterface interface {
+	SelectAuthorById(ctx context.Context, id uint) (*models.Author, error)
+	SelectBooksByAuthorId(ctx context.Context, authorId uint) ([]*models.Book, error)
+}
@@ -0,0 +1,29 @@
+package books
+
+import (
+	"context"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+)
+
+type ListAuthorBooksService struct {
+	repository ListAuthorBooksRepositoryInterface
+}
+
+func NewListAuthorBooksService(repository ListAuthorBooksRepositoryInterface) *ListAuthorBooksService {
+	return &ListAuthorBooksService{
+		repository: repository,
+	}
+}
+
+func (s *ListAuthorBooksService) ListAuthorBooks(ctx context.Context, authorId uint) ([]*models.Book, error) {
+	// Check if author exist
+	author, err := s.repository.SelectAuthorById(ctx, authorId)
+	if err != nil {
+		return nil, err
+	}
+	if author == nil {
+		return nil, ErrAuthorNotFound
+	}
+	return s.repository.SelectBooksByAuthorId(ctx, authorId)
+}
@@ -0,0 +1,17 @@
+package books
+
+import (
+	"context"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+)
+
+// ListAuthorsServiceInterface Inbound port
+type ListAuthorsServiceInterface interface {
+	ListAuthors(ctx context.Context) ([]models.Author, error)
+}
+
+// ListAuthorsRepositoryInterface Outbound port
+type ListAuthorsRepositoryInterface interface {
+	SelectAuthors(ctx context.Context) ([]models.Author, error)
+}
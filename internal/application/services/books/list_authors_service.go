@@ -0,0 +1,21 @@
+package books
+
+import (
+	"context"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+)
+
+type ListAuthorsService struct {
+	repository ListAuthorsRepositoryInterface
+}
+
+func NewListAuthorsService(repository ListAuthorsRepositoryInterface) *ListAuthorsService {
+	return &ListAuthorsService{
+		repository: repository,
+	}
+}
+
+func (s *ListAuthorsService) ListAuthors(ctx context.Context) ([]models.Author, error) {
+	return s.repository.SelectAuthors(ctx)
+}
@@ -0,0 +1,18 @@
+package books
+
+import (
+	"context"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+)
+
+// ListBooksServiceInterface Inbound port
+type ListBooksServiceInterface interface {
+	ListBooks(ctx context.Context, query *models.BookListQuery) (*models.BookPage, error)
+}
+
+// ListBooksRepositoryInterface Outbound port
+type ListBooksRepositoryInterface interface {
+	// SelectBooks returns an error wrapping ErrInvalidListQuery when the cursor cannot be decoded
+	SelectBooks(ctx context.Context, query *models.BookListQuery) (*models.BookPage, error)
+}
@@ -0,0 +1,49 @@
+package books
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+)
+
+const (
+	DefaultListBooksLimit = 20
+	MaxListBooksLimit     = 100
+)
+
+type ListBooksService struct {
+	repository ListBooksRepositoryInterface
+}
+
+func NewListBooksService(repository ListBooksRepositoryInterface) *ListBooksService {
+	return &ListBooksService{
+		repository: repository,
+	}
+}
+
+func (s *ListBooksService) ListBooks(ctx context.Context, query *models.BookListQuery) (*models.BookPage, error) {
+	// Apply defaults
+	listQuery := *query
+	if listQuery.SortBy == "" {
+		listQuery.SortBy = models.BookSortByCreatedAt
+	}
+	// The books are stored with the canonical ISBN-13, digits only
+	listQuery.IsbnPrefix = models.StripIsbnSeparators(listQuery.IsbnPrefix)
+	if listQuery.Limit == 0 {
+		listQuery.Limit = DefaultListBooksLimit
+	}
+	// Validate
+	if listQuery.SortBy != models.BookSortByCreatedAt && listQuery.SortBy != models.BookSortByTitle {
+		return nil, fmt.Errorf("%w: books cannot be sorted by %q", ErrInvalidListQuery, listQuery.SortBy)
+	}
+	if listQuery.Limit < 0 || listQuery.Limit > MaxListBooksLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidListQuery, MaxListBooksLimit)
+	}
+	page, err := s.repository.SelectBooks(ctx, &listQuery)
+	if err != nil {
+		return nil, err
+	}
+	page.Limit = listQuery.Limit
+	return page, nil
+}
@@ -0,0 +1,44 @@
+package books
+
+import (
+	"context"
+	"slices"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+)
+
+// NotificationSettings routes the notifications of an environment
+type NotificationSettings struct {
+	// Channels are the enabled channels, the recipients of any other channel are skipped
+	Channels []string
+	// Recipients receive every notification, on top of the subscribers stored in the database
+	Recipients []models.NotificationRecipient
+}
+
+// NotificationSubscribersRepositoryInterface Outbound port
+type NotificationSubscribersRepositoryInterface interface {
+	SelectNotificationSubscribers(ctx context.Context, event string) ([]models.NotificationRecipient, error)
+}
+
+// newNotifications returns a notification of the event for every recipient of an enabled channel, without duplicates
+func newNotifications(ctx context.Context, repository NotificationSubscribersRepositoryInterface, settings NotificationSettings,
+	event string, book *models.Book) ([]models.Notification, error) {
+	subscribers, err := repository.SelectNotificationSubscribers(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+	var notifications []models.Notification
+	seen := map[models.NotificationRecipient]bool{}
+	for _, recipient := range append(slices.Clone(settings.Recipients), subscribers...) {
+		if seen[recipient] || !slices.Contains(settings.Channels, recipient.Channel) {
+			continue
+		}
+		seen[recipient] = true
+		notifications = append(notifications, models.Notification{
+			Event:     event,
+			Recipient: recipient,
+			Book:      *book,
+		})
+	}
+	return notifications, nil
+}
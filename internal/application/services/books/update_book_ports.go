@@ -0,0 +1,19 @@
+package books
+
+import (
+	"context"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+)
+
+// UpdateBookServiceInterface Inbound port
+type UpdateBookServiceInterface interface {
+	UpdateBook(ctx context.Context, book *models.Book) (*models.Book, error)
+}
+
+// UpdateBookRepositoryInterface Outbound port
+type UpdateBookRepositoryInterface interface {
+	AuthorReferencesRepositoryInterface
+	SelectBookByIsbn(ctx context.Context, isbn string) (*models.Book, error)
+	UpdateBook(ctx context.Context, book *models.Book) (*models.Book, error)
+}
@@ -0,0 +1,42 @@
+package books
+
+import (
+	"context"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+)
+
+type UpdateBookService struct {
+	repository UpdateBookRepositoryInterface
+}
+
+func NewUpdateBookService(repository UpdateBookRepositoryInterface) *UpdateBookService {
+	return &UpdateBookService{
+		repository: repository,
+	}
+}
+
+// UpdateBook replaces the stored book identified by book.Isbn
+func (s *UpdateBookService) UpdateBook(ctx context.Context, book *models.Book) (*models.Book, error) {
+	isbn, err := models.NormalizeIsbn(book.Isbn)
+	if err != nil {
+		return nil, err
+	}
+	normalizedBook := *book
+	normalizedBook.Isbn = isbn
+	book = &normalizedBook
+	// Check if book exist
+	exist, err := s.repository.SelectBookByIsbn(ctx, book.Isbn)
+	if err != nil {
+		return nil, err
+	}
+	if exist == nil {
+		return nil, ErrBookNotFound
+	}
+	// Check if the authors exist
+	book.Authors, err = resolveAuthors(ctx, s.repository, book.Authors)
+	if err != nil {
+		return nil, err
+	}
+	return s.repository.UpdateBook(ctx, book)
+}
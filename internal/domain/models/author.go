@@ -0,0 +1,6 @@
+package models
+
+type Author struct {
+	ID   uint
+	Name string
+}
@@ -1,7 +1,19 @@
 package models
 
+import "time"
+
 type Book struct {
-	// Isbn: International Standard Book Number
+	// Isbn: International Standard Book Number, always the canonical ISBN-13. See ParseIsbn
 	Isbn  string
 	Title string
+	// Authors only need the ID to reference them when creating or updating a book
+	Authors []Author
+	// TotalPages is 0 when unknown
+	TotalPages int
+	Publisher  string
+	// PublicationDate is nil when unknown, only the date is meaningful
+	PublicationDate *time.Time
+	// Language is a BCP 47 language tag, e.g. "en" or "en-US"
+	Language    string
+	Description string
 }
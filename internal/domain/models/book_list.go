@@ -0,0 +1,33 @@
+package models
+
+// BookSortField is the field used to sort a list of books
+type BookSortField string
+
+const (
+	BookSortByCreatedAt BookSortField = "created_at"
+	BookSortByTitle     BookSortField = "title"
+)
+
+// BookListQuery describes which page of books to list
+type BookListQuery struct {
+	// TitleContains keeps the books whose title contains it, case-insensitive
+	TitleContains string
+	// IsbnPrefix keeps the books whose ISBN starts with it
+	IsbnPrefix string
+	SortBy     BookSortField
+	Descending bool
+	Limit      int
+	// Cursor is the opaque NextCursor of the previous page, empty for the first page
+	Cursor string
+}
+
+// BookPage is one page of a book listing
+type BookPage struct {
+	Books []*Book
+	// Limit is the maximum number of books of the page
+	Limit int
+	// TotalCount is the number of books matching the filters, across all the pages
+	TotalCount int64
+	// NextCursor is empty when there are no more pages
+	NextCursor string
+}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidIsbn is returned when a string is not a valid ISBN-10 or ISBN-13
+var ErrInvalidIsbn = errors.New("invalid isbn")
+
+// Isbn International Standard Book Number.
+// The value is always the canonical ISBN-13, digits only, so the different ways of writing
+// the same book ("0-061-96436-0", "0061964360", "978-0-06-196436-7") are equal.
+type Isbn struct {
+	value string
+}
+
+// ParseIsbn parses a hyphenated or bare ISBN-10 or ISBN-13 and verifies its check digit
+func ParseIsbn(raw string) (Isbn, error) {
+	digits := StripIsbnSeparators(raw)
+	switch len(digits) {
+	case 10:
+		if !isValidIsbn10(digits) {
+			return Isbn{}, fmt.Errorf("%w: %q has a wrong ISBN-10 check digit", ErrInvalidIsbn, raw)
+		}
+		body := "978" + digits[:9]
+		return Isbn{value: body + isbn13CheckDigit(body)}, nil
+	case 13:
+		if !isDigits(digits) || !strings.HasPrefix(digits, "978") && !strings.HasPrefix(digits, "979") {
+			return Isbn{}, fmt.Errorf("%w: %q is not an ISBN-13", ErrInvalidIsbn, raw)
+		}
+		if isbn13CheckDigit(digits[:12]) != digits[12:] {
+			return Isbn{}, fmt.Errorf("%w: %q has a wrong ISBN-13 check digit", ErrInvalidIsbn, raw)
+		}
+		return Isbn{value: digits}, nil
+	default:
+		return Isbn{}, fmt.Errorf("%w: %q must have 10 or 13 digits", ErrInvalidIsbn, raw)
+	}
+}
+
+// NormalizeIsbn returns the canonical ISBN-13 of raw
+func NormalizeIsbn(raw string) (string, error) {
+	isbn, err := ParseIsbn(raw)
+	if err != nil {
+		return "", err
+	}
+	return isbn.String(), nil
+}
+
+// StripIsbnSeparators removes the hyphens and spaces used to group the ISBN digits
+func StripIsbnSeparators(raw string) string {
+	return strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(raw))
+}
+
+// String returns the canonical ISBN-13
+func (i Isbn) String() string {
+	return i.value
+}
+
+func isValidIsbn10(digits string) bool {
+	sum := 0
+	for i, r := range digits {
+		var digit int
+		switch {
+		case r >= '0' && r <= '9':
+			digit = int(r - '0')
+		case (r == 'X' || r == 'x') && i == 9:
+			// X stands for 10, only as check digit
+			digit = 10
+		default:
+			return false
+		}
+		sum += (10 - i) * digit
+	}
+	return sum%11 == 0
+}
+
+// isbn13CheckDigit computes the check digit of the first 12 digits of an ISBN-13
+func isbn13CheckDigit(body string) string {
+	sum := 0
+	for i, r := range body {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += weight * int(r-'0')
+	}
+	return string(rune('0' + (10-sum%10)%10))
+}
+
+func isDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
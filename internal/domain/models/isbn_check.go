@@ -0,0 +1,10 @@
+package models
+
+// IsbnCheck is the answer of the external ISBN service about an ISBN
+type IsbnCheck struct {
+	Valid bool
+	// CanonicalId is the ISBN as written by the service, empty when it does not send it
+	CanonicalId string
+	// Title is the title known by the service, empty when it does not send it
+	Title string
+}
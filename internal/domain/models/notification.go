@@ -0,0 +1,48 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NotificationEventBookCreated is notified when a book is created
+const NotificationEventBookCreated = "book_created"
+
+// The channels deliver the notifications, each one understands its own kind of address
+const (
+	// NotificationChannelEmailApi sends an email through the HTTP email API, the address is an email
+	NotificationChannelEmailApi = "email_api"
+	// NotificationChannelSmtp sends an email through an SMTP server, the address is an email
+	NotificationChannelSmtp = "smtp"
+	// NotificationChannelWebhook posts the notification as JSON, the address is a URL
+	NotificationChannelWebhook = "webhook"
+)
+
+// NotificationRecipient is who receives a notification and through which channel
+type NotificationRecipient struct {
+	Channel string
+	Address string
+}
+
+// ParseNotificationRecipient parses a recipient written as channel:address, e.g. "email_api:books@example.com"
+func ParseNotificationRecipient(raw string) (NotificationRecipient, error) {
+	channel, address, found := strings.Cut(strings.TrimSpace(raw), ":")
+	if !found || channel == "" || address == "" {
+		return NotificationRecipient{}, fmt.Errorf("invalid notification recipient %q, it must be channel:address", raw)
+	}
+	return NotificationRecipient{Channel: channel, Address: address}, nil
+}
+
+func (r NotificationRecipient) String() string {
+	return r.Channel + ":" + r.Address
+}
+
+// Notification is a notification about a book waiting in the outbox to be delivered to one recipient
+type Notification struct {
+	ID        uint
+	Event     string
+	Recipient NotificationRecipient
+	Book      Book
+	// Attempts counts the failed deliveries so far
+	Attempts int
+}
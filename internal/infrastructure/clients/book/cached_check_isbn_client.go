@@ -0,0 +1,93 @@
+package book
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	servicebook "github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/application/services/books"
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+)
+
+// IsbnCheckCache stores the answers of the ISBN service until they expire
+type IsbnCheckCache interface {
+	// Get returns nil when the ISBN is not cached or its entry expired
+	Get(ctx context.Context, isbn string) (*models.IsbnCheck, error)
+	Set(ctx context.Context, isbn string, isbnCheck *models.IsbnCheck, ttl time.Duration) error
+}
+
+type IsbnCheckCacheOptions struct {
+	// PositiveTTL is how long a valid ISBN is cached
+	PositiveTTL time.Duration
+	// NegativeTTL is how long a rejected ISBN is cached, shorter so a fix in the ISBN service is seen soon
+	NegativeTTL time.Duration
+}
+
+var DefaultIsbnCheckCacheOptions = IsbnCheckCacheOptions{
+	PositiveTTL: 24 * time.Hour,
+	NegativeTTL: 5 * time.Minute,
+}
+
+// IsbnCheckCacheStats counts the lookups served by the cache and the ones sent to the ISBN service
+type IsbnCheckCacheStats struct {
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+}
+
+// CachedCheckIsbnClient decorates a CheckIsbnClientInterface with a cache.
+// Only the answers are cached, the errors are not, so an ISBN service outage is not remembered.
+type CachedCheckIsbnClient struct {
+	checkIsbnClientInterface servicebook.CheckIsbnClientInterface
+	cache                    IsbnCheckCache
+	options                  IsbnCheckCacheOptions
+	logger                   *slog.Logger
+	hits                     atomic.Uint64
+	misses                   atomic.Uint64
+}
+
+func NewCachedCheckIsbnClient(checkIsbnClientInterface servicebook.CheckIsbnClientInterface,
+	cache IsbnCheckCache,
+	options IsbnCheckCacheOptions,
+	logger *slog.Logger) *CachedCheckIsbnClient {
+	return &CachedCheckIsbnClient{
+		checkIsbnClientInterface: checkIsbnClientInterface,
+		cache:                    cache,
+		options:                  options,
+		logger:                   logger,
+	}
+}
+
+func (c *CachedCheckIsbnClient) CheckIsbn(ctx context.Context, isbn string) (*models.IsbnCheck, error) {
+	// A broken cache must not break the book creation, it only costs a call to the ISBN service
+	cached, err := c.cache.Get(ctx, isbn)
+	if err != nil {
+		c.logger.WarnContext(ctx, "Error reading the ISBN cache", slog.String("isbn", isbn), slog.Any("error", err))
+	}
+	if cached != nil {
+		c.hits.Add(1)
+		return cached, nil
+	}
+	c.misses.Add(1)
+	isbnCheck, err := c.checkIsbnClientInterface.CheckIsbn(ctx, isbn)
+	if err != nil {
+		return nil, err
+	}
+	ttl := c.options.PositiveTTL
+	if !isbnCheck.Valid {
+		ttl = c.options.NegativeTTL
+	}
+	if ttl > 0 {
+		if err := c.cache.Set(ctx, isbn, isbnCheck, ttl); err != nil {
+			c.logger.WarnContext(ctx, "Error writing the ISBN cache", slog.String("isbn", isbn), slog.Any("error", err))
+		}
+	}
+	return isbnCheck, nil
+}
+
+func (c *CachedCheckIsbnClient) Stats() IsbnCheckCacheStats {
+	return IsbnCheckCacheStats{
+		Hits:   c.hits.Load(),
+		Misses: c.misses.Load(),
+	}
+}
@@ -1,8 +1,12 @@
 package book
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+
+	servicebook "github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/application/services/books"
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
 )
 
 type CheckIsbnClient struct {
@@ -19,21 +23,42 @@ func NewCheckIsbnClient(host string, httpClient *http.Client) *CheckIsbnClient {
 	}
 }
 
-func (c *CheckIsbnClient) CheckIsbn(isbn string) (bool, error) {
+// CheckIsbnResponseBody is the payload of a known ISBN
+type CheckIsbnResponseBody struct {
+	Id    string `json:"id"`
+	Title string `json:"title,omitempty"`
+}
+
+func (c *CheckIsbnClient) CheckIsbn(ctx context.Context, isbn string) (*models.IsbnCheck, error) {
 	// Create a new request
 	url := c.host + fmt.Sprintf(c.checkIsbnPath, isbn)
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 	// Send request
 	res, err := c.httpClient.Do(req)
 	if err != nil {
-		return false, err
+		return nil, fmt.Errorf("%w: checking isbn: %w", servicebook.ErrUpstreamUnavailable, err)
 	}
-	// Check response status
-	if res.StatusCode == http.StatusOK {
-		return true, nil
+	defer drainAndClose(res.Body)
+	// Check response status, only a client error means that the ISBN was rejected
+	switch {
+	case res.StatusCode == http.StatusOK:
+		checkIsbnResponseBody := CheckIsbnResponseBody{}
+		if err := decodeResponseBody(res.Body, &checkIsbnResponseBody, "checking isbn"); err != nil {
+			return nil, err
+		}
+		return &models.IsbnCheck{
+			Valid:       true,
+			CanonicalId: checkIsbnResponseBody.Id,
+			Title:       checkIsbnResponseBody.Title,
+		}, nil
+	case res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusBadRequest || res.StatusCode == http.StatusUnprocessableEntity:
+		return &models.IsbnCheck{Valid: false}, nil
+	case res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= http.StatusInternalServerError:
+		return nil, fmt.Errorf("%w: checking isbn: status code %d", servicebook.ErrUpstreamUnavailable, res.StatusCode)
+	default:
+		return nil, fmt.Errorf("%w: checking isbn: status code %d", servicebook.ErrUpstreamBadResponse, res.StatusCode)
 	}
-	return false, nil
 }
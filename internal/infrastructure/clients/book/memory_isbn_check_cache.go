@@ -0,0 +1,76 @@
+package book
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+)
+
+// MemoryIsbnCheckCache is an IsbnCheckCache in memory, lost on restart.
+// It holds up to maxEntries ISBNs and evicts the least recently used one when full.
+type MemoryIsbnCheckCache struct {
+	maxEntries int
+	mutex      sync.Mutex
+	// entries is ordered from the most to the least recently used
+	entries *list.List
+	byIsbn  map[string]*list.Element
+}
+
+type memoryIsbnCheckCacheEntry struct {
+	isbn      string
+	isbnCheck models.IsbnCheck
+	expiresAt time.Time
+}
+
+func NewMemoryIsbnCheckCache(maxEntries int) *MemoryIsbnCheckCache {
+	return &MemoryIsbnCheckCache{
+		maxEntries: maxEntries,
+		entries:    list.New(),
+		byIsbn:     map[string]*list.Element{},
+	}
+}
+
+func (c *MemoryIsbnCheckCache) Get(ctx context.Context, isbn string) (*models.IsbnCheck, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	element, ok := c.byIsbn[isbn]
+	if !ok {
+		return nil, nil
+	}
+	entry := element.Value.(*memoryIsbnCheckCacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.remove(element)
+		return nil, nil
+	}
+	c.entries.MoveToFront(element)
+	isbnCheck := entry.isbnCheck
+	return &isbnCheck, nil
+}
+
+func (c *MemoryIsbnCheckCache) Set(ctx context.Context, isbn string, isbnCheck *models.IsbnCheck, ttl time.Duration) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	entry := &memoryIsbnCheckCacheEntry{
+		isbn:      isbn,
+		isbnCheck: *isbnCheck,
+		expiresAt: time.Now().Add(ttl),
+	}
+	if element, ok := c.byIsbn[isbn]; ok {
+		element.Value = entry
+		c.entries.MoveToFront(element)
+		return nil
+	}
+	c.byIsbn[isbn] = c.entries.PushFront(entry)
+	for c.entries.Len() > c.maxEntries && c.entries.Len() > 0 {
+		c.remove(c.entries.Back())
+	}
+	return nil
+}
+
+func (c *MemoryIsbnCheckCache) remove(element *list.Element) {
+	c.entries.Remove(element)
+	delete(c.byIsbn, element.Value.(*memoryIsbnCheckCacheEntry).isbn)
+}
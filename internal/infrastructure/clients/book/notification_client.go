@@ -0,0 +1,57 @@
+package book
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+)
+
+// NotificationMessage is a rendered notification, ready to be delivered by a channel
+type NotificationMessage struct {
+	// IdempotencyKey is the same on every attempt of a notification, so the receivers can drop the duplicates
+	IdempotencyKey string
+	Event          string
+	Address        string
+	Subject        string
+	Body           string
+	Book           *models.Book
+}
+
+// NotificationChannel delivers the messages of a channel, e.g. the HTTP email API
+type NotificationChannel interface {
+	Send(ctx context.Context, message *NotificationMessage) error
+}
+
+// NotificationClient renders the notifications and hands them to the channel of their recipient
+type NotificationClient struct {
+	templates *NotificationTemplates
+	channels  map[string]NotificationChannel
+}
+
+func NewNotificationClient(templates *NotificationTemplates, channels map[string]NotificationChannel) *NotificationClient {
+	return &NotificationClient{
+		templates: templates,
+		channels:  channels,
+	}
+}
+
+func (c *NotificationClient) SendNotification(ctx context.Context, notification *models.Notification) error {
+	channel, ok := c.channels[notification.Recipient.Channel]
+	if !ok {
+		return fmt.Errorf("notification channel %q is not enabled", notification.Recipient.Channel)
+	}
+	subject, body, err := c.templates.Render(notification)
+	if err != nil {
+		return err
+	}
+	return channel.Send(ctx, &NotificationMessage{
+		IdempotencyKey: "notification-" + strconv.FormatUint(uint64(notification.ID), 10),
+		Event:          notification.Event,
+		Address:        notification.Recipient.Address,
+		Subject:        subject,
+		Body:           body,
+		Book:           &notification.Book,
+	})
+}
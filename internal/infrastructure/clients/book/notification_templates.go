@@ -0,0 +1,64 @@
+package book
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+)
+
+//go:embed templates/*.tmpl
+var defaultTemplates embed.FS
+
+// NotificationTemplates renders the subject and the body of the notifications.
+// The templates of an event are <event>.subject.tmpl and <event>.body.tmpl, text/template files
+// executed with the Notification, e.g. {{ .Book.Title }}.
+type NotificationTemplates struct {
+	templates *template.Template
+}
+
+// NewNotificationTemplates loads the templates of dir, or the default ones when dir is empty
+func NewNotificationTemplates(dir string) (*NotificationTemplates, error) {
+	var source fs.FS
+	if dir == "" {
+		source, _ = fs.Sub(defaultTemplates, "templates") //nolint:errcheck // the directory is embedded
+	} else {
+		source = os.DirFS(dir)
+	}
+	templates, err := template.New("notifications").Funcs(template.FuncMap{
+		"authorNames": authorNames,
+		"join": func(separator string, values []string) string {
+			return strings.Join(values, separator)
+		},
+	}).ParseFS(source, "*.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("error loading the notification templates: %w", err)
+	}
+	return &NotificationTemplates{templates: templates}, nil
+}
+
+// Render returns the subject and the body of a notification
+func (t *NotificationTemplates) Render(notification *models.Notification) (string, string, error) {
+	subject, err := t.execute(notification.Event+".subject.tmpl", notification)
+	if err != nil {
+		return "", "", err
+	}
+	body, err := t.execute(notification.Event+".body.tmpl", notification)
+	if err != nil {
+		return "", "", err
+	}
+	return subject, body, nil
+}
+
+func (t *NotificationTemplates) execute(name string, notification *models.Notification) (string, error) {
+	text := strings.Builder{}
+	if err := t.templates.ExecuteTemplate(&text, name, notification); err != nil {
+		return "", fmt.Errorf("error rendering the notification template %s: %w", name, err)
+	}
+	// The files end with a new line that is not part of the text
+	return strings.TrimSpace(text.String()), nil
+}
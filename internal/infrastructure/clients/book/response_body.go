@@ -0,0 +1,27 @@
+package book
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	servicebook "github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/application/services/books"
+)
+
+// maxResponseBodyBytes bounds what is read from an upstream, a larger body is a bad response
+const maxResponseBodyBytes = 1 << 20
+
+// decodeResponseBody decodes the JSON body of an upstream response into v
+func decodeResponseBody(body io.Reader, v interface{}, operation string) error {
+	decoder := json.NewDecoder(io.LimitReader(body, maxResponseBodyBytes))
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("%w: %s: decoding response: %v", servicebook.ErrUpstreamBadResponse, operation, err)
+	}
+	return nil
+}
+
+// drainAndClose reads what is left of the body, so the connection goes back to the pool, and closes it
+func drainAndClose(body io.ReadCloser) {
+	io.Copy(io.Discard, io.LimitReader(body, maxResponseBodyBytes)) //nolint:errcheck // the body is discarded anyway
+	body.Close()
+}
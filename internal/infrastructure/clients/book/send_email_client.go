@@ -2,13 +2,19 @@ package book
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"errors"
-	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
-	"log"
+	"fmt"
 	"net/http"
+	"strings"
+	"time"
+
+	servicebook "github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/application/services/books"
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/infrastructure/clients/resilience"
 )
 
+// SendEmailClient is the notification channel of the HTTP email API
 type SendEmailClient struct {
 	httpClient    *http.Client
 	host          string
@@ -25,40 +31,89 @@ func NewSendEmailClient(host string, httpClient *http.Client) *SendEmailClient {
 
 type SendEmailBookRequestBody struct {
 	// Isbn: International Standard Book Number
-	Isbn  string `json:"isbn"`
-	Title string `json:"title"`
+	Isbn            string   `json:"isbn"`
+	Title           string   `json:"title"`
+	Authors         []string `json:"authors,omitempty"`
+	TotalPages      int      `json:"total_pages,omitempty"`
+	Publisher       string   `json:"publisher,omitempty"`
+	PublicationDate string   `json:"publication_date,omitempty"`
+	Language        string   `json:"language,omitempty"`
+	Description     string   `json:"description,omitempty"`
 }
 
 type SendEmailRequestBody struct {
-	Email string                   `json:"email"`
-	Book  SendEmailBookRequestBody `json:"book"`
+	Email   string                   `json:"email"`
+	Subject string                   `json:"subject"`
+	Body    string                   `json:"body"`
+	Book    SendEmailBookRequestBody `json:"book"`
+}
+
+// SendEmailResponseBody is the payload of an accepted email
+type SendEmailResponseBody struct {
+	Status string `json:"status"`
 }
 
-func (c *SendEmailClient) SendEmail(email string, book *models.Book) error {
+// sendEmailStatusOk is the status of an accepted email, compared ignoring the case
+const sendEmailStatusOk = "ok"
+
+func (c *SendEmailClient) Send(ctx context.Context, message *NotificationMessage) error {
 	// Create a new request
 	sendEmailRequestBody := SendEmailRequestBody{
-		Email: email,
-		Book: SendEmailBookRequestBody{
-			Isbn:  book.Isbn,
-			Title: book.Title,
-		},
+		Email:   message.Address,
+		Subject: message.Subject,
+		Body:    message.Body,
+		Book:    mapToSendEmailBookRequestBody(message.Book),
 	}
 	sendEmailRequestBodyJson, err := json.Marshal(sendEmailRequestBody)
 	if err != nil {
-		log.Fatalf("Error marshalling sendEmailRequestBody on SendEmailClient.SendEmail: %s", err)
+		return fmt.Errorf("error marshalling the send email request: %w", err)
 	}
-	req, err := http.NewRequest(http.MethodPost, c.host+c.checkIsbnPath, bytes.NewReader(sendEmailRequestBodyJson))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.host+c.checkIsbnPath, bytes.NewReader(sendEmailRequestBodyJson))
 	if err != nil {
 		return err
 	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(resilience.IdempotencyKeyHeader, message.IdempotencyKey)
 	// Send request
 	res, err := c.httpClient.Do(req)
 	if err != nil {
-		return err
+		return fmt.Errorf("%w: sending email: %w", servicebook.ErrUpstreamUnavailable, err)
 	}
+	defer drainAndClose(res.Body)
 	// Check response status
-	if res.StatusCode == http.StatusOK {
-		return nil
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: sending email: status code %d", servicebook.ErrUpstreamBadResponse, res.StatusCode)
+	}
+	sendEmailResponseBody := SendEmailResponseBody{}
+	if err := decodeResponseBody(res.Body, &sendEmailResponseBody, "sending email"); err != nil {
+		return err
+	}
+	if !strings.EqualFold(sendEmailResponseBody.Status, sendEmailStatusOk) {
+		return fmt.Errorf("%w: sending email: status %q", servicebook.ErrUpstreamBadResponse, sendEmailResponseBody.Status)
+	}
+	return nil
+}
+
+func mapToSendEmailBookRequestBody(book *models.Book) SendEmailBookRequestBody {
+	sendEmailBookRequestBody := SendEmailBookRequestBody{
+		Isbn:        book.Isbn,
+		Title:       book.Title,
+		Authors:     authorNames(book.Authors),
+		TotalPages:  book.TotalPages,
+		Publisher:   book.Publisher,
+		Language:    book.Language,
+		Description: book.Description,
+	}
+	if book.PublicationDate != nil {
+		sendEmailBookRequestBody.PublicationDate = book.PublicationDate.Format(time.DateOnly)
+	}
+	return sendEmailBookRequestBody
+}
+
+func authorNames(authors []models.Author) []string {
+	names := make([]string, 0, len(authors))
+	for _, author := range authors {
+		names = append(names, author.Name)
 	}
-	return errors.New("Error sending email")
+	return names
 }
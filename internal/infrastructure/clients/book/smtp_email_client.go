@@ -0,0 +1,108 @@
+package book
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	servicebook "github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/application/services/books"
+)
+
+type SmtpOptions struct {
+	Host string
+	Port int
+	// Username and Password are optional, PLAIN authentication is only used when Username is set
+	Username string
+	Password string
+	From     string
+	// Timeout bounds the whole conversation with the server, unless the context has an earlier deadline
+	Timeout time.Duration
+}
+
+// SmtpEmailClient is the notification channel that sends the emails through an SMTP server
+type SmtpEmailClient struct {
+	options SmtpOptions
+}
+
+func NewSmtpEmailClient(options SmtpOptions) *SmtpEmailClient {
+	return &SmtpEmailClient{
+		options: options,
+	}
+}
+
+func (c *SmtpEmailClient) Send(ctx context.Context, message *NotificationMessage) error {
+	addr := net.JoinHostPort(c.options.Host, strconv.Itoa(c.options.Port))
+	// net/smtp has no context, the deadline bounds the whole conversation instead
+	deadline, ok := ctx.Deadline()
+	if c.options.Timeout > 0 && (!ok || time.Until(deadline) > c.options.Timeout) {
+		deadline, ok = time.Now().Add(c.options.Timeout), true
+	}
+	dialer := net.Dialer{}
+	if ok {
+		dialer.Deadline = deadline
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("%w: sending email through smtp: %v", servicebook.ErrUpstreamUnavailable, err)
+	}
+	if ok {
+		conn.SetDeadline(deadline) //nolint:errcheck // a failed deadline only loses the bound
+	}
+	client, err := smtp.NewClient(conn, c.options.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("%w: sending email through smtp: %v", servicebook.ErrUpstreamUnavailable, err)
+	}
+	defer client.Close()
+	if err := c.send(client, message); err != nil {
+		return fmt.Errorf("%w: sending email through smtp: %v", servicebook.ErrUpstreamBadResponse, err)
+	}
+	return client.Quit()
+}
+
+func (c *SmtpEmailClient) send(client *smtp.Client, message *NotificationMessage) error {
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: c.options.Host}); err != nil {
+			return err
+		}
+	}
+	if c.options.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", c.options.Username, c.options.Password, c.options.Host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(c.options.From); err != nil {
+		return err
+	}
+	if err := client.Rcpt(message.Address); err != nil {
+		return err
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(c.email(message)); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// email formats the message as a plain text email
+func (c *SmtpEmailClient) email(message *NotificationMessage) []byte {
+	headers := []string{
+		"From: " + c.options.From,
+		"To: " + message.Address,
+		"Subject: " + mime.QEncoding.Encode("utf-8", message.Subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"Message-ID: <" + message.IdempotencyKey + "@" + c.options.Host + ">",
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=utf-8",
+	}
+	return []byte(strings.Join(headers, "\r\n") + "\r\n\r\n" + strings.ReplaceAll(message.Body, "\n", "\r\n") + "\r\n")
+}
@@ -0,0 +1,204 @@
+package book
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	servicebook "github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/application/services/books"
+)
+
+// fakeSmtpServer is an SMTP server answering a single client, it records the commands and the data it receives
+type fakeSmtpServer struct {
+	listener net.Listener
+	// startTls advertises STARTTLS, the TLS handshake records the server name sent by the client
+	startTls   bool
+	serverName chan string
+	// silent accepts the connection without ever greeting the client
+	silent   bool
+	commands chan []string
+	data     chan string
+}
+
+func newFakeSmtpServer(t *testing.T) *fakeSmtpServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	return &fakeSmtpServer{
+		listener:   listener,
+		serverName: make(chan string, 1),
+		commands:   make(chan []string, 1),
+		data:       make(chan string, 1),
+	}
+}
+
+func (s *fakeSmtpServer) options() SmtpOptions {
+	_, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+	return SmtpOptions{
+		// localhost instead of the IP, so the client sends it as the TLS server name
+		Host:    "localhost",
+		Port:    portNumber,
+		From:    "books@example.com",
+		Timeout: 5 * time.Second,
+	}
+}
+
+func (s *fakeSmtpServer) serve() {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	if s.silent {
+		// Wait until the client gives up
+		conn.Read(make([]byte, 1)) //nolint:errcheck // only the end of the connection matters
+		return
+	}
+	reader := bufio.NewReader(conn)
+	reply := func(lines ...string) {
+		conn.Write([]byte(strings.Join(lines, "\r\n") + "\r\n")) //nolint:errcheck // the client reports the failures
+	}
+	var commands []string
+	defer func() { s.commands <- commands }()
+	reply("220 localhost ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.TrimRight(line, "\r\n")
+		commands = append(commands, command)
+		switch verb := strings.ToUpper(strings.Fields(command)[0]); verb {
+		case "EHLO":
+			if s.startTls {
+				reply("250-localhost", "250 STARTTLS")
+			} else {
+				reply("250 localhost")
+			}
+		case "STARTTLS":
+			reply("220 Ready to start TLS")
+			tlsConn := tls.Server(conn, &tls.Config{
+				GetCertificate: func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+					s.serverName <- hello.ServerName
+					return nil, errors.New("no certificate")
+				},
+			})
+			tlsConn.Handshake() //nolint:errcheck // the handshake is only used to record the server name
+			return
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(dataLine)
+			}
+			s.data <- data.String()
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestSmtpEmailClientSendsTheEmail(t *testing.T) {
+	server := newFakeSmtpServer(t)
+	go server.serve()
+	client := NewSmtpEmailClient(server.options())
+
+	err := client.Send(context.Background(), &NotificationMessage{
+		IdempotencyKey: "42",
+		Address:        "reader@example.com",
+		Subject:        "New book: Fundamental Algorithms",
+		Body:           "Fundamental Algorithms has been added to the catalog.",
+	})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	commands := <-server.commands
+	for _, expected := range []string{"MAIL FROM:<books@example.com>", "RCPT TO:<reader@example.com>", "DATA", "QUIT"} {
+		if !containsPrefix(commands, expected) {
+			t.Errorf("commands = %q, want %q", commands, expected)
+		}
+	}
+	data := <-server.data
+	for _, expected := range []string{
+		"To: reader@example.com\r\n",
+		"Subject: New book: Fundamental Algorithms\r\n",
+		"Message-ID: <42@localhost>\r\n",
+		"\r\n\r\nFundamental Algorithms has been added to the catalog.\r\n",
+	} {
+		if !strings.Contains(data, expected) {
+			t.Errorf("data = %q, want %q", data, expected)
+		}
+	}
+}
+
+func TestSmtpEmailClientStartsTlsWithTheServerName(t *testing.T) {
+	server := newFakeSmtpServer(t)
+	server.startTls = true
+	go server.serve()
+	client := NewSmtpEmailClient(server.options())
+
+	err := client.Send(context.Background(), &NotificationMessage{Address: "reader@example.com"})
+
+	// The fake server has no certificate, so the handshake fails once the server name is sent
+	if !errors.Is(err, servicebook.ErrUpstreamBadResponse) {
+		t.Errorf("Send() error = %v, want %v", err, servicebook.ErrUpstreamBadResponse)
+	}
+	select {
+	case serverName := <-server.serverName:
+		if serverName != "localhost" {
+			t.Errorf("server name = %q, want %q", serverName, "localhost")
+		}
+	case <-time.After(time.Second):
+		t.Error("the client did not start the TLS handshake")
+	}
+}
+
+func TestSmtpEmailClientGivesUpAfterTheTimeout(t *testing.T) {
+	server := newFakeSmtpServer(t)
+	server.silent = true
+	go server.serve()
+	options := server.options()
+	options.Timeout = 100 * time.Millisecond
+	client := NewSmtpEmailClient(options)
+
+	start := time.Now()
+	err := client.Send(context.Background(), &NotificationMessage{Address: "reader@example.com"})
+
+	if !errors.Is(err, servicebook.ErrUpstreamUnavailable) {
+		t.Errorf("Send() error = %v, want %v", err, servicebook.ErrUpstreamUnavailable)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Send() took %v, want about %v", elapsed, options.Timeout)
+	}
+}
+
+func containsPrefix(values []string, prefix string) bool {
+	for _, value := range values {
+		if strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+	return false
+}
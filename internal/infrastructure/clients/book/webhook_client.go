@@ -0,0 +1,60 @@
+package book
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	servicebook "github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/application/services/books"
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/infrastructure/clients/resilience"
+)
+
+// WebhookClient is the notification channel that posts the notifications as JSON to the URL of the recipient
+type WebhookClient struct {
+	httpClient *http.Client
+}
+
+func NewWebhookClient(httpClient *http.Client) *WebhookClient {
+	return &WebhookClient{
+		httpClient: httpClient,
+	}
+}
+
+type WebhookRequestBody struct {
+	Event   string                   `json:"event"`
+	Subject string                   `json:"subject"`
+	Body    string                   `json:"body"`
+	Book    SendEmailBookRequestBody `json:"book"`
+}
+
+func (c *WebhookClient) Send(ctx context.Context, message *NotificationMessage) error {
+	// Create a new request
+	webhookRequestBodyJson, err := json.Marshal(WebhookRequestBody{
+		Event:   message.Event,
+		Subject: message.Subject,
+		Body:    message.Body,
+		Book:    mapToSendEmailBookRequestBody(message.Book),
+	})
+	if err != nil {
+		return fmt.Errorf("error marshalling the webhook request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, message.Address, bytes.NewReader(webhookRequestBodyJson))
+	if err != nil {
+		return fmt.Errorf("invalid webhook url %q: %w", message.Address, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(resilience.IdempotencyKeyHeader, message.IdempotencyKey)
+	// Send request
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: calling webhook: %w", servicebook.ErrUpstreamUnavailable, err)
+	}
+	defer drainAndClose(res.Body)
+	// Any 2xx is accepted, the body is ignored
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%w: calling webhook: status code %d", servicebook.ErrUpstreamBadResponse, res.StatusCode)
+	}
+	return nil
+}
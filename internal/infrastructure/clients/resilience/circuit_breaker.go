@@ -0,0 +1,103 @@
+package resilience
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without calling the host while its circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// circuitBreaker stops calling a host after FailureThreshold consecutive failures.
+// Once OpenTimeout has passed a single probe request is let through: it closes the circuit on success
+// and opens it again on failure.
+type circuitBreaker struct {
+	failureThreshold int
+	openTimeout      time.Duration
+
+	mutex               sync.Mutex
+	state               circuitState
+	consecutiveFailures int
+	openedAt            time.Time
+}
+
+func newCircuitBreaker(failureThreshold int, openTimeout time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+	}
+}
+
+// allow reports whether a request can be sent, and reserves the probe when the circuit is half open
+func (b *circuitBreaker) allow() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	switch b.state {
+	case circuitOpen:
+		if time.Since(b.openedAt) < b.openTimeout {
+			return ErrCircuitOpen
+		}
+		b.state = circuitHalfOpen
+		return nil
+	case circuitHalfOpen:
+		// The probe is in flight
+		return ErrCircuitOpen
+	default:
+		return nil
+	}
+}
+
+func (b *circuitBreaker) record(success bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if success {
+		b.state = circuitClosed
+		b.consecutiveFailures = 0
+		return
+	}
+	b.consecutiveFailures++
+	if b.state == circuitHalfOpen || b.consecutiveFailures >= b.failureThreshold {
+		b.state = circuitOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// abandon releases the probe reserved by allow without an outcome, e.g. when the request was cancelled
+func (b *circuitBreaker) abandon() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.state == circuitHalfOpen {
+		// Back to open, but the next request is a probe again
+		b.state = circuitOpen
+		b.openedAt = time.Time{}
+	}
+}
+
+// circuitBreakers holds one circuit breaker per host
+type circuitBreakers struct {
+	failureThreshold int
+	openTimeout      time.Duration
+
+	mutex    sync.Mutex
+	breakers map[string]*circuitBreaker
+}
+
+func (c *circuitBreakers) get(host string) *circuitBreaker {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	breaker, ok := c.breakers[host]
+	if !ok {
+		breaker = newCircuitBreaker(c.failureThreshold, c.openTimeout)
+		c.breakers[host] = breaker
+	}
+	return breaker
+}
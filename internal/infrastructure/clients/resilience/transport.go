@@ -0,0 +1,187 @@
+// Package resilience provides an http.RoundTripper that retries the failed idempotent requests
+// and stops calling a host that keeps failing.
+package resilience
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+// IdempotencyKeyHeader marks a non-idempotent request, such as a POST, as safe to retry
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+type Options struct {
+	// MaxAttempts counts the first attempt, 1 disables the retries
+	MaxAttempts int
+	// BaseDelay is the maximum wait before the first retry, doubled on every retry up to MaxDelay.
+	// The actual wait is a random duration up to that maximum, so the clients do not retry in lockstep.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// FailureThreshold is the number of consecutive failures that opens the circuit breaker of a host
+	FailureThreshold int
+	// OpenTimeout is how long the circuit breaker stays open before letting a probe request through
+	OpenTimeout time.Duration
+	// Logger logs the retries, nothing is logged when nil
+	Logger *slog.Logger
+}
+
+var DefaultOptions = Options{
+	MaxAttempts:      3,
+	BaseDelay:        100 * time.Millisecond,
+	MaxDelay:         2 * time.Second,
+	FailureThreshold: 5,
+	OpenTimeout:      30 * time.Second,
+}
+
+type Transport struct {
+	base     http.RoundTripper
+	options  Options
+	breakers *circuitBreakers
+}
+
+// NewTransport wraps base, http.DefaultTransport when nil
+func NewTransport(base http.RoundTripper, options Options) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{
+		base:    base,
+		options: options,
+		breakers: &circuitBreakers{
+			failureThreshold: options.FailureThreshold,
+			openTimeout:      options.OpenTimeout,
+			breakers:         map[string]*circuitBreaker{},
+		},
+	}
+}
+
+// RoundTrip sends the request through the circuit breaker of its host.
+// Transport errors and the 429, 502, 503 and 504 responses are retried when the request is idempotent,
+// the last response or error is returned once the attempts are exhausted.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	breaker := t.breakers.get(req.URL.Host)
+	maxAttempts := 1
+	if isRetryable(req) {
+		maxAttempts = max(t.options.MaxAttempts, 1)
+	}
+	for attempt := 1; ; attempt++ {
+		if err := breaker.allow(); err != nil {
+			return nil, fmt.Errorf("%w for %s", err, req.URL.Host)
+		}
+		attemptReq, err := rewindBody(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+		res, err := t.base.RoundTrip(attemptReq)
+		failed := err != nil || res.StatusCode >= http.StatusInternalServerError
+		// A cancelled request says nothing about the health of the host
+		if req.Context().Err() == nil {
+			breaker.record(!failed)
+		} else {
+			breaker.abandon()
+		}
+		if attempt >= maxAttempts || !shouldRetry(res, err) || req.Context().Err() != nil {
+			return res, err
+		}
+		if t.options.Logger != nil {
+			t.logRetry(req, attempt, res, err)
+		}
+		if res != nil {
+			// Drain the body so the connection can be reused by the next attempt
+			io.Copy(io.Discard, res.Body) //nolint:errcheck // the response is discarded anyway
+			res.Body.Close()
+		}
+		if err := sleep(req.Context(), t.backoff(attempt)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// CloseIdleConnections closes the idle connections of the base transport, so http.Client.CloseIdleConnections reaches it
+func (t *Transport) CloseIdleConnections() {
+	if closer, ok := t.base.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
+func (t *Transport) logRetry(req *http.Request, attempt int, res *http.Response, err error) {
+	attrs := []slog.Attr{
+		slog.String("host", req.URL.Host),
+		slog.String("upstream_method", req.Method),
+		slog.Int("attempt", attempt),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	} else {
+		attrs = append(attrs, slog.Int("upstream_status", res.StatusCode))
+	}
+	t.options.Logger.LogAttrs(req.Context(), slog.LevelWarn, "Retrying upstream request", attrs...)
+}
+
+// backoff returns a random delay up to BaseDelay * 2^(attempt-1), bounded by MaxDelay
+func (t *Transport) backoff(attempt int) time.Duration {
+	delay := t.options.BaseDelay
+	for i := 1; i < attempt && delay < t.options.MaxDelay; i++ {
+		delay *= 2
+	}
+	delay = min(delay, t.options.MaxDelay)
+	if delay <= 0 {
+		return 0
+	}
+	return rand.N(delay) + 1
+}
+
+func isRetryable(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		// The body could not be sent twice
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return req.Header.Get(IdempotencyKeyHeader) != ""
+	}
+}
+
+func shouldRetry(res *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch res.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// rewindBody returns the request to send on the given attempt, with a fresh body after the first one
+func rewindBody(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 1 || req.GetBody == nil {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	attemptReq := req.Clone(req.Context())
+	attemptReq.Body = body
+	return attemptReq, nil
+}
+
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
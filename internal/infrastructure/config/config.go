@@ -0,0 +1,260 @@
+// Package config loads the configuration of the application.
+//
+// Every field has a default, overridden by the optional YAML or JSON file of CONFIG_FILE,
+// overridden in turn by its environment variable. The struct tags drive the loading:
+//   - yaml: the key in the file, JSON files use the same keys.
+//   - env: the environment variable.
+//   - default: the value when neither the file nor the environment set it.
+//   - required: the value must not be empty.
+//   - secret: the value is redacted when printed.
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/infrastructure/logging"
+)
+
+// FileEnvVar is the environment variable with the path of the optional configuration file
+const FileEnvVar = "CONFIG_FILE"
+
+type Config struct {
+	Log           LogConfig           `yaml:"log"`
+	Server        ServerConfig        `yaml:"server"`
+	CORS          CORSConfig          `yaml:"cors"`
+	Database      DatabaseConfig      `yaml:"database"`
+	HttpClient    HttpClientConfig    `yaml:"http_client"`
+	CheckIsbn     CheckIsbnConfig     `yaml:"check_isbn"`
+	IsbnCache     IsbnCacheConfig     `yaml:"isbn_cache"`
+	Notifications NotificationsConfig `yaml:"notifications"`
+}
+
+type LogConfig struct {
+	// Level is debug, info, warn or error
+	Level string `yaml:"level" env:"LOG_LEVEL" default:"info"`
+}
+
+type ServerConfig struct {
+	Address string `yaml:"address" env:"SERVER_ADDRESS" default:":8000" required:"true"`
+	// RequestTimeout is the timeout of the routes without their own
+	RequestTimeout time.Duration `yaml:"request_timeout" env:"SERVER_REQUEST_TIMEOUT" default:"10s"`
+	// RouteTimeouts are the timeouts of some routes as pattern=duration, e.g. POST /api/v1/books=30s
+	RouteTimeouts []string `yaml:"route_timeouts" env:"SERVER_ROUTE_TIMEOUTS" default:"POST /api/v1/books=30s,POST /api/v1/createBook=30s"`
+	// The timeouts of the connections, the write timeout must be longer than the longest route timeout
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" env:"SERVER_READ_HEADER_TIMEOUT" default:"5s"`
+	ReadTimeout       time.Duration `yaml:"read_timeout" env:"SERVER_READ_TIMEOUT" default:"30s"`
+	WriteTimeout      time.Duration `yaml:"write_timeout" env:"SERVER_WRITE_TIMEOUT" default:"45s"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout" env:"SERVER_IDLE_TIMEOUT" default:"2m"`
+	// ShutdownTimeout is how long the in-flight requests are drained on SIGTERM, shorter than the grace period of the pod
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SERVER_SHUTDOWN_TIMEOUT" default:"25s"`
+}
+
+type CORSConfig struct {
+	// AllowedOrigins are the origins allowed to call the API, * allows any. CORS is disabled when empty.
+	AllowedOrigins []string      `yaml:"allowed_origins" env:"CORS_ALLOWED_ORIGINS"`
+	AllowedMethods []string      `yaml:"allowed_methods" env:"CORS_ALLOWED_METHODS" default:"GET,POST,PUT,PATCH,DELETE"`
+	AllowedHeaders []string      `yaml:"allowed_headers" env:"CORS_ALLOWED_HEADERS" default:"Accept,Content-Type,X-Request-ID"`
+	MaxAge         time.Duration `yaml:"max_age" env:"CORS_MAX_AGE" default:"10m"`
+}
+
+type DatabaseConfig struct {
+	Host     string `yaml:"host" env:"DATABASE_HOST" required:"true"`
+	Port     int    `yaml:"port" env:"DATABASE_PORT" default:"5432"`
+	User     string `yaml:"user" env:"DATABASE_USER" required:"true"`
+	Password string `yaml:"password" env:"DATABASE_PASSWORD" required:"true" secret:"true"`
+	Name     string `yaml:"name" env:"DATABASE_NAME" required:"true"`
+	SslMode  string `yaml:"ssl_mode" env:"DATABASE_SSL_MODE" default:"disable"`
+}
+
+type HttpClientConfig struct {
+	Timeout time.Duration `yaml:"timeout" env:"HTTP_CLIENT_TIMEOUT" default:"5s"`
+	// MaxAttempts counts the first attempt of a request
+	MaxAttempts        int           `yaml:"max_attempts" env:"HTTP_CLIENT_MAX_ATTEMPTS" default:"3"`
+	RetryDelay         time.Duration `yaml:"retry_delay" env:"HTTP_CLIENT_RETRY_DELAY" default:"100ms"`
+	BreakerFailures    int           `yaml:"breaker_failures" env:"HTTP_CLIENT_BREAKER_FAILURES" default:"5"`
+	BreakerOpenTimeout time.Duration `yaml:"breaker_open_timeout" env:"HTTP_CLIENT_BREAKER_OPEN_TIMEOUT" default:"30s"`
+}
+
+type CheckIsbnConfig struct {
+	Host string `yaml:"host" env:"CHECK_ISBN_CLIENT_HOST" required:"true"`
+}
+
+// The stores of the ISBN cache
+const (
+	IsbnCacheMemory   = "memory"
+	IsbnCachePostgres = "postgres"
+	IsbnCacheNone     = "none"
+)
+
+type IsbnCacheConfig struct {
+	Store       string        `yaml:"store" env:"ISBN_CACHE" default:"memory"`
+	TTL         time.Duration `yaml:"ttl" env:"ISBN_CACHE_TTL" default:"24h"`
+	NegativeTTL time.Duration `yaml:"negative_ttl" env:"ISBN_CACHE_NEGATIVE_TTL" default:"5m"`
+	MaxEntries  int           `yaml:"max_entries" env:"ISBN_CACHE_MAX_ENTRIES" default:"10000"`
+}
+
+type NotificationsConfig struct {
+	Channels []string `yaml:"channels" env:"NOTIFICATION_CHANNELS" default:"email_api"`
+	// Recipients are written as channel:address
+	Recipients         []string      `yaml:"recipients" env:"NOTIFICATION_RECIPIENTS" default:"email_api:helloworld@gmail.com"`
+	TemplatesDir       string        `yaml:"templates_dir" env:"NOTIFICATION_TEMPLATES_DIR"`
+	OutboxPollInterval time.Duration `yaml:"outbox_poll_interval" env:"NOTIFICATION_OUTBOX_POLL_INTERVAL" default:"1s"`
+	OutboxRetryDelay   time.Duration `yaml:"outbox_retry_delay" env:"NOTIFICATION_OUTBOX_RETRY_DELAY" default:"1s"`
+	// OutboxLease hides a notification from the other dispatchers while it is sent, it must outlast the slowest send
+	OutboxLease time.Duration  `yaml:"outbox_lease" env:"NOTIFICATION_OUTBOX_LEASE" default:"1m"`
+	EmailApi    EmailApiConfig `yaml:"email_api"`
+	Smtp        SmtpConfig     `yaml:"smtp"`
+}
+
+type EmailApiConfig struct {
+	Host string `yaml:"host" env:"EMAIL_CLIENT_HOST"`
+}
+
+type SmtpConfig struct {
+	Host     string `yaml:"host" env:"SMTP_HOST"`
+	Port     int    `yaml:"port" env:"SMTP_PORT" default:"587"`
+	Username string `yaml:"username" env:"SMTP_USERNAME"`
+	Password string `yaml:"password" env:"SMTP_PASSWORD" secret:"true"`
+	From     string `yaml:"from" env:"SMTP_FROM"`
+	// Timeout bounds the conversation with the server
+	Timeout time.Duration `yaml:"timeout" env:"SMTP_TIMEOUT" default:"30s"`
+}
+
+// DSN returns the connection string of the database, it contains the password so it must not be printed
+func (c DatabaseConfig) DSN() string {
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		c.Host, c.Port, c.User, c.Password, c.Name, c.SslMode)
+}
+
+// Validate checks the database section alone
+func (c DatabaseConfig) Validate() error {
+	if problems := requiredProblems(&c); len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// ParseRouteTimeouts parses the timeouts of the routes
+func (c ServerConfig) ParseRouteTimeouts() (map[string]time.Duration, error) {
+	routeTimeouts := make(map[string]time.Duration, len(c.RouteTimeouts))
+	for _, routeTimeout := range c.RouteTimeouts {
+		pattern, rawTimeout, ok := strings.Cut(routeTimeout, "=")
+		if !ok || pattern == "" {
+			return nil, fmt.Errorf("invalid route timeout %q, it must be pattern=duration", routeTimeout)
+		}
+		timeout, err := time.ParseDuration(rawTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid route timeout %q: %w", routeTimeout, err)
+		}
+		routeTimeouts[pattern] = timeout
+	}
+	return routeTimeouts, nil
+}
+
+// NotificationRecipients parses the recipients
+func (c NotificationsConfig) NotificationRecipients() ([]models.NotificationRecipient, error) {
+	recipients := make([]models.NotificationRecipient, 0, len(c.Recipients))
+	for _, rawRecipient := range c.Recipients {
+		recipient, err := models.ParseNotificationRecipient(rawRecipient)
+		if err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, recipient)
+	}
+	return recipients, nil
+}
+
+// maxNotificationSendTime returns the longest a notification of the enabled channels can take to send.
+// An HTTP send makes up to HTTP_CLIENT_MAX_ATTEMPTS attempts of HTTP_CLIENT_TIMEOUT, waiting up to
+// HTTP_CLIENT_RETRY_DELAY doubled on every retry in between. An SMTP send is bounded by SMTP_TIMEOUT.
+func (c *Config) maxNotificationSendTime() time.Duration {
+	var maxSendTime time.Duration
+	for _, channel := range c.Notifications.Channels {
+		switch channel {
+		case models.NotificationChannelEmailApi, models.NotificationChannelWebhook:
+			sendTime := time.Duration(c.HttpClient.MaxAttempts) * c.HttpClient.Timeout
+			for delay, retry := c.HttpClient.RetryDelay, 1; retry < c.HttpClient.MaxAttempts; delay, retry = delay*2, retry+1 {
+				sendTime += delay
+			}
+			maxSendTime = max(maxSendTime, sendTime)
+		case models.NotificationChannelSmtp:
+			maxSendTime = max(maxSendTime, c.Notifications.Smtp.Timeout)
+		}
+	}
+	return maxSendTime
+}
+
+// Validate checks the required values and the values that depend on each other, reporting every problem at once
+func (c *Config) Validate() error {
+	var problems []string
+	problems = append(problems, requiredProblems(c)...)
+	if _, err := logging.ParseLevel(c.Log.Level); err != nil {
+		problems = append(problems, fmt.Sprintf("LOG_LEVEL must be debug, info, warn or error, not %q", c.Log.Level))
+	}
+	routeTimeouts, err := c.Server.ParseRouteTimeouts()
+	if err != nil {
+		problems = append(problems, "SERVER_ROUTE_TIMEOUTS: "+err.Error())
+	}
+	// A request cut by the write timeout gets no response at all, so it must outlast the route timeouts
+	for _, timeout := range append(slices.Collect(maps.Values(routeTimeouts)), c.Server.RequestTimeout) {
+		if c.Server.WriteTimeout > 0 && timeout >= c.Server.WriteTimeout {
+			problems = append(problems, "SERVER_WRITE_TIMEOUT must be longer than SERVER_REQUEST_TIMEOUT and SERVER_ROUTE_TIMEOUTS")
+			break
+		}
+	}
+	if !slices.Contains([]string{IsbnCacheMemory, IsbnCachePostgres, IsbnCacheNone}, c.IsbnCache.Store) {
+		problems = append(problems, fmt.Sprintf("ISBN_CACHE must be memory, postgres or none, not %q", c.IsbnCache.Store))
+	}
+	for _, channel := range c.Notifications.Channels {
+		switch channel {
+		case models.NotificationChannelEmailApi:
+			if c.Notifications.EmailApi.Host == "" {
+				problems = append(problems, "EMAIL_CLIENT_HOST is required by the email_api notification channel")
+			}
+		case models.NotificationChannelSmtp:
+			if c.Notifications.Smtp.Host == "" || c.Notifications.Smtp.From == "" {
+				problems = append(problems, "SMTP_HOST and SMTP_FROM are required by the smtp notification channel")
+			}
+		case models.NotificationChannelWebhook:
+		default:
+			problems = append(problems, fmt.Sprintf("NOTIFICATION_CHANNELS must be email_api, smtp or webhook, not %q", channel))
+		}
+	}
+	if _, err := c.Notifications.NotificationRecipients(); err != nil {
+		problems = append(problems, "NOTIFICATION_RECIPIENTS: "+err.Error())
+	}
+	for name, duration := range map[string]time.Duration{
+		"HTTP_CLIENT_TIMEOUT":               c.HttpClient.Timeout,
+		"SERVER_READ_HEADER_TIMEOUT":        c.Server.ReadHeaderTimeout,
+		"SERVER_READ_TIMEOUT":               c.Server.ReadTimeout,
+		"SERVER_WRITE_TIMEOUT":              c.Server.WriteTimeout,
+		"SERVER_IDLE_TIMEOUT":               c.Server.IdleTimeout,
+		"SERVER_SHUTDOWN_TIMEOUT":           c.Server.ShutdownTimeout,
+		"NOTIFICATION_OUTBOX_POLL_INTERVAL": c.Notifications.OutboxPollInterval,
+		"SMTP_TIMEOUT":                      c.Notifications.Smtp.Timeout,
+	} {
+		if duration <= 0 {
+			problems = append(problems, name+" must be positive")
+		}
+	}
+	if c.HttpClient.MaxAttempts < 1 {
+		problems = append(problems, "HTTP_CLIENT_MAX_ATTEMPTS must be at least 1")
+	}
+	if c.IsbnCache.MaxEntries < 1 {
+		problems = append(problems, "ISBN_CACHE_MAX_ENTRIES must be at least 1")
+	}
+	// A notification still being sent when its lease expires is claimed and sent again by another dispatcher
+	if maxSendTime := c.maxNotificationSendTime(); c.Notifications.OutboxLease <= maxSendTime {
+		problems = append(problems, fmt.Sprintf("NOTIFICATION_OUTBOX_LEASE must be longer than the slowest notification send, %s with the HTTP client and SMTP timeouts", maxSendTime))
+	}
+	if len(problems) > 0 {
+		slices.Sort(problems)
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
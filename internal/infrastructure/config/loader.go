@@ -0,0 +1,214 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// redacted replaces the secrets when the configuration is printed
+const redacted = "******"
+
+// ValidationError lists every invalid value of the configuration
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration: " + strings.Join(e.Problems, "; ")
+}
+
+// Default returns the configuration with the defaults only, it is not valid until the required values are set
+func Default() *Config {
+	config := &Config{}
+	if err := walk(config, func(field reflect.Value, tag reflect.StructTag) error {
+		value, ok := tag.Lookup("default")
+		if !ok {
+			return nil
+		}
+		return setField(field, value)
+	}); err != nil {
+		// The defaults are constants, so this is a programming error
+		panic("invalid default configuration: " + err.Error())
+	}
+	return config
+}
+
+// Load reads the configuration from the file of CONFIG_FILE, if any, and the environment, then validates it
+func Load() (*Config, error) {
+	config, err := Read(os.Getenv(FileEnvVar), os.LookupEnv)
+	if err != nil {
+		return nil, err
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// Read reads the configuration from the file, skipped when empty, and the environment of lookupEnv.
+// It is not validated, so the commands that only need a part of it can validate that part.
+func Read(file string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	config := Default()
+	if file != "" {
+		if err := readFile(file, config); err != nil {
+			return nil, err
+		}
+	}
+	err := walk(config, func(field reflect.Value, tag reflect.StructTag) error {
+		key := tag.Get("env")
+		value, ok := lookupEnv(key)
+		if key == "" || !ok {
+			return nil
+		}
+		if err := setField(field, value); err != nil {
+			return fmt.Errorf("invalid %s: %w", key, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// readFile decodes a YAML or a JSON file, JSON being a subset of YAML. Unknown keys are rejected to catch typos.
+func readFile(file string, config *Config) error {
+	switch extension := strings.ToLower(filepath.Ext(file)); extension {
+	case ".yaml", ".yml", ".json":
+	default:
+		return fmt.Errorf("unsupported configuration file %s, it must be .yaml, .yml or .json", file)
+	}
+	content, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("error reading the configuration file: %w", err)
+	}
+	defer content.Close()
+	decoder := yaml.NewDecoder(content)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil {
+		return fmt.Errorf("error decoding the configuration file %s: %w", file, err)
+	}
+	return nil
+}
+
+// Redacted returns a copy of the configuration with the secrets replaced, safe to print
+func (c *Config) Redacted() *Config {
+	copied := *c
+	copied.Notifications.Channels = append([]string(nil), c.Notifications.Channels...)
+	copied.Notifications.Recipients = append([]string(nil), c.Notifications.Recipients...)
+	walk(&copied, func(field reflect.Value, tag reflect.StructTag) error { //nolint:errcheck // the function never fails
+		if tag.Get("secret") == "true" && field.String() != "" {
+			field.SetString(redacted)
+		}
+		return nil
+	})
+	return &copied
+}
+
+// String prints the configuration as YAML, with the secrets redacted
+func (c *Config) String() string {
+	out, err := yaml.Marshal(c.Redacted())
+	if err != nil {
+		return "invalid configuration: " + err.Error()
+	}
+	return string(out)
+}
+
+// LogValue logs the configuration as its environment variables, with the secrets redacted
+func (c *Config) LogValue() slog.Value {
+	var attrs []slog.Attr
+	walk(c.Redacted(), func(field reflect.Value, tag reflect.StructTag) error { //nolint:errcheck // the function never fails
+		key := tag.Get("env")
+		switch {
+		case field.Type() == durationType:
+			attrs = append(attrs, slog.String(key, time.Duration(field.Int()).String()))
+		case field.Kind() == reflect.Slice:
+			attrs = append(attrs, slog.String(key, strings.Join(field.Interface().([]string), ",")))
+		default:
+			attrs = append(attrs, slog.Any(key, field.Interface()))
+		}
+		return nil
+	})
+	return slog.GroupValue(attrs...)
+}
+
+// requiredProblems reports the empty required fields of v, a pointer to the Config or to one of its sections
+func requiredProblems(v interface{}) []string {
+	var problems []string
+	walkStruct(reflect.ValueOf(v).Elem(), func(field reflect.Value, tag reflect.StructTag) error { //nolint:errcheck // the function never fails
+		if tag.Get("required") == "true" && field.IsZero() {
+			problems = append(problems, tag.Get("env")+" is required")
+		}
+		return nil
+	})
+	return problems
+}
+
+// walk calls fn with every leaf field of config and its tags
+func walk(config *Config, fn func(field reflect.Value, tag reflect.StructTag) error) error {
+	return walkStruct(reflect.ValueOf(config).Elem(), fn)
+}
+
+func walkStruct(value reflect.Value, fn func(field reflect.Value, tag reflect.StructTag) error) error {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		structField := value.Type().Field(i)
+		if field.Kind() == reflect.Struct {
+			if err := walkStruct(field, fn); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := fn(field, structField.Tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// setField parses value into the field. The lists are comma separated.
+func setField(field reflect.Value, value string) error {
+	switch {
+	case field.Type() == durationType:
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(duration))
+	case field.Kind() == reflect.String:
+		field.SetString(value)
+	case field.Kind() == reflect.Int:
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(number))
+	case field.Kind() == reflect.Bool:
+		boolean, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(boolean)
+	case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.String:
+		var list []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		field.Set(reflect.ValueOf(list))
+	default:
+		return fmt.Errorf("unsupported configuration type %s", field.Type())
+	}
+	return nil
+}
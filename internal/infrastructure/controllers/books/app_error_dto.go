@@ -1,6 +1,31 @@
 package books
 
+import "github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/infrastructure/controllers/utils"
+
+// Error codes of the ErrorResponse. They are stable, clients can rely on them instead of the error message.
+const (
+	ErrorCodeInvalidPayload         = "INVALID_PAYLOAD"
+	ErrorCodeValidationFailed       = "VALIDATION_FAILED"
+	ErrorCodePayloadTooLarge        = "PAYLOAD_TOO_LARGE"
+	ErrorCodeInvalidQueryParameters = "INVALID_QUERY_PARAMETERS"
+	ErrorCodeInvalidListQuery       = "INVALID_LIST_QUERY"
+	ErrorCodeInvalidIsbn            = "INVALID_ISBN"
+	ErrorCodeBookNotFound           = "BOOK_NOT_FOUND"
+	ErrorCodeBookAlreadyExists      = "BOOK_ALREADY_EXISTS"
+	ErrorCodeAuthorNotFound         = "AUTHOR_NOT_FOUND"
+	ErrorCodeUnknownAuthor          = "UNKNOWN_AUTHOR"
+	ErrorCodeUpstreamUnavailable    = "UPSTREAM_UNAVAILABLE"
+	ErrorCodeUpstreamBadResponse    = "UPSTREAM_BAD_RESPONSE"
+	ErrorCodeMethodNotAllowed       = "METHOD_NOT_ALLOWED"
+	ErrorCodeNotFound               = "NOT_FOUND"
+	ErrorCodeInternal               = "INTERNAL_ERROR"
+	ErrorCodeRequestTimeout         = "REQUEST_TIMEOUT"
+)
+
 type ErrorResponse struct {
+	Code    string `json:"code"`
 	Message string `json:"message"`
 	Error   string `json:"error"`
+	// Errors lists every invalid field of the payload
+	Errors []utils.FieldError `json:"errors,omitempty"`
 }
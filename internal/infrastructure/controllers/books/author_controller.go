@@ -0,0 +1,105 @@
+package books
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	servicebook "github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/application/services/books"
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/infrastructure/controllers/books/dto"
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/infrastructure/controllers/utils"
+)
+
+type AuthorController struct {
+	createAuthorServiceInterface    servicebook.CreateAuthorServiceInterface
+	getAuthorServiceInterface       servicebook.GetAuthorServiceInterface
+	listAuthorsServiceInterface     servicebook.ListAuthorsServiceInterface
+	listAuthorBooksServiceInterface servicebook.ListAuthorBooksServiceInterface
+	logger                          *slog.Logger
+}
+
+func NewAuthorController(createAuthorServiceInterface servicebook.CreateAuthorServiceInterface,
+	getAuthorServiceInterface servicebook.GetAuthorServiceInterface,
+	listAuthorsServiceInterface servicebook.ListAuthorsServiceInterface,
+	listAuthorBooksServiceInterface servicebook.ListAuthorBooksServiceInterface,
+	logger *slog.Logger) *AuthorController {
+	return &AuthorController{
+		createAuthorServiceInterface:    createAuthorServiceInterface,
+		getAuthorServiceInterface:       getAuthorServiceInterface,
+		listAuthorsServiceInterface:     listAuthorsServiceInterface,
+		listAuthorBooksServiceInterface: listAuthorBooksServiceInterface,
+		logger:                          logger,
+	}
+}
+
+// authorId parses the {id} of the path. An invalid id is not found, as no author has it.
+func authorId(w http.ResponseWriter, req *http.Request) (uint, bool) {
+	id, err := strconv.ParseUint(req.PathValue("id"), 10, 0)
+	if err != nil {
+		writeError(w, req, http.StatusNotFound, ErrorCodeNotFound, "The author id must be a number", nil)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// CreateAuthor handles POST /api/v1/authors
+func (c *AuthorController) CreateAuthor(w http.ResponseWriter, req *http.Request) {
+	createAuthorRequest := new(dto.CreateAuthorRequest)
+	if !decodeAndValidate(w, req, createAuthorRequest) {
+		return
+	}
+	// mapper
+	authorDomain := dto.MapToAuthorModel(createAuthorRequest)
+	// service
+	author, err := c.createAuthorServiceInterface.CreateAuthor(req.Context(), authorDomain)
+	if err != nil {
+		writeServiceError(w, req, c.logger, err, "Error creating author")
+		return
+	}
+	// response
+	utils.Response(w, dto.MapToAuthorResponse(author), http.StatusOK)
+}
+
+// ListAuthors handles GET /api/v1/authors
+func (c *AuthorController) ListAuthors(w http.ResponseWriter, req *http.Request) {
+	// service
+	authors, err := c.listAuthorsServiceInterface.ListAuthors(req.Context())
+	if err != nil {
+		writeServiceError(w, req, c.logger, err, "Error listing authors")
+		return
+	}
+	// response
+	utils.Response(w, dto.MapToListAuthorsResponse(authors), http.StatusOK)
+}
+
+// GetAuthor handles GET /api/v1/authors/{id}
+func (c *AuthorController) GetAuthor(w http.ResponseWriter, req *http.Request) {
+	id, ok := authorId(w, req)
+	if !ok {
+		return
+	}
+	// service
+	author, err := c.getAuthorServiceInterface.GetAuthor(req.Context(), id)
+	if err != nil {
+		writeServiceError(w, req, c.logger, err, "Error getting author")
+		return
+	}
+	// response
+	utils.Response(w, dto.MapToAuthorResponse(author), http.StatusOK)
+}
+
+// ListAuthorBooks handles GET /api/v1/authors/{id}/books
+func (c *AuthorController) ListAuthorBooks(w http.ResponseWriter, req *http.Request) {
+	id, ok := authorId(w, req)
+	if !ok {
+		return
+	}
+	// service
+	books, err := c.listAuthorBooksServiceInterface.ListAuthorBooks(req.Context(), id)
+	if err != nil {
+		writeServiceError(w, req, c.logger, err, "Error listing author books")
+		return
+	}
+	// response
+	utils.Response(w, dto.MapToListAuthorBooksResponse(books), http.StatusOK)
+}
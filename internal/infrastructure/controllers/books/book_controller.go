@@ -1,67 +1,140 @@
 package books
 
 import (
+	"log/slog"
+	"net/http"
+
 	servicebook "github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/application/services/books"
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
 	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/infrastructure/controllers/books/dto"
 	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/infrastructure/controllers/utils"
-	"net/http"
 )
 
 type Controller struct {
 	createBookServiceInterface servicebook.CreateBookServiceInterface
+	getBookServiceInterface    servicebook.GetBookServiceInterface
+	listBooksServiceInterface  servicebook.ListBooksServiceInterface
+	updateBookServiceInterface servicebook.UpdateBookServiceInterface
+	deleteBookServiceInterface servicebook.DeleteBookServiceInterface
+	logger                     *slog.Logger
 }
 
-func NewBookController(createBookServiceInterface servicebook.CreateBookServiceInterface) *Controller {
+func NewBookController(createBookServiceInterface servicebook.CreateBookServiceInterface,
+	getBookServiceInterface servicebook.GetBookServiceInterface,
+	listBooksServiceInterface servicebook.ListBooksServiceInterface,
+	updateBookServiceInterface servicebook.UpdateBookServiceInterface,
+	deleteBookServiceInterface servicebook.DeleteBookServiceInterface,
+	logger *slog.Logger) *Controller {
 	return &Controller{
 		createBookServiceInterface: createBookServiceInterface,
+		getBookServiceInterface:    getBookServiceInterface,
+		listBooksServiceInterface:  listBooksServiceInterface,
+		updateBookServiceInterface: updateBookServiceInterface,
+		deleteBookServiceInterface: deleteBookServiceInterface,
+		logger:                     logger,
 	}
 }
 
+// CreateBook handles POST /api/v1/books
 func (c *Controller) CreateBook(w http.ResponseWriter, req *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	if req.Method == "POST" {
-		createBookRequest := new(dto.CreateBookRequest)
-		err := utils.Decode(req, &createBookRequest)
-		if err != nil {
-			errorResponse := ErrorResponse{
-				Message: "Bad Request. Invalid payload",
-				Error:   err.Error(),
-			}
-			utils.Response(w, errorResponse, http.StatusBadRequest)
-			return
+	createBookRequest := new(dto.CreateBookRequest)
+	if !decodeAndValidate(w, req, createBookRequest) {
+		return
+	}
+	// mapper
+	bookDomain := dto.MapToBookModel(createBookRequest)
+	// service
+	createBook, err := c.createBookServiceInterface.CreateBook(req.Context(), bookDomain)
+	if err != nil {
+		writeServiceError(w, req, c.logger, err, "Error creating book")
+		return
+	}
+	// response
+	createBookResponse := dto.MapToCreateBookResponse(createBook)
+	if err = utils.Response(w, createBookResponse, http.StatusOK); err != nil {
+		writeError(w, req, http.StatusInternalServerError, ErrorCodeInternal, "Error encoding response", err)
+		return
+	}
+}
+
+// ListBooks handles GET /api/v1/books
+func (c *Controller) ListBooks(w http.ResponseWriter, req *http.Request) {
+	// mapper
+	listBooksRequest := dto.MapToListBooksRequest(req.URL.Query())
+	bookListQuery, err := dto.MapToBookListQuery(listBooksRequest)
+	if err != nil {
+		writeError(w, req, http.StatusBadRequest, ErrorCodeInvalidQueryParameters, "Invalid query parameters", err)
+		return
+	}
+	// service
+	bookPage, err := c.listBooksServiceInterface.ListBooks(req.Context(), bookListQuery)
+	if err != nil {
+		writeServiceError(w, req, c.logger, err, "Error listing books")
+		return
+	}
+	// response
+	utils.Response(w, dto.MapToListBooksResponse(bookPage), http.StatusOK)
+}
 
-		}
+// GetBook handles GET /api/v1/books/{isbn}
+func (c *Controller) GetBook(w http.ResponseWriter, req *http.Request) {
+	// service
+	book, err := c.getBookServiceInterface.GetBook(req.Context(), req.PathValue("isbn"))
+	if err != nil {
+		writeServiceError(w, req, c.logger, err, "Error getting book")
+		return
+	}
+	// response
+	utils.Response(w, dto.MapToGetBookResponse(book), http.StatusOK)
+}
 
-		// mapper
-		bookDomain := dto.MapToBookModel(createBookRequest)
-		// service
-		createBook, err := c.createBookServiceInterface.CreateBook(bookDomain)
-		if err != nil {
-			errorResponse := ErrorResponse{
-				Message: "Bad Request. Error creating book",
-				Error:   err.Error(),
-			}
-			utils.Response(w, errorResponse, http.StatusBadRequest)
-			return
-		}
-		// response
-		createBookResponse := dto.MapToCreateBookResponse(createBook)
+// UpdateBook handles PUT /api/v1/books/{isbn}
+func (c *Controller) UpdateBook(w http.ResponseWriter, req *http.Request) {
+	updateBookRequest := new(dto.UpdateBookRequest)
+	if !decodeAndValidate(w, req, updateBookRequest) {
+		return
+	}
+	// mapper
+	bookDomain := dto.MapUpdateBookRequestToBookModel(req.PathValue("isbn"), updateBookRequest)
+	c.updateBook(w, req, bookDomain)
+}
 
-		if err = utils.Response(w, createBookResponse, http.StatusOK); err != nil {
-			errorResponse := ErrorResponse{
-				Message: "Internal Server Error",
-				Error:   err.Error(),
-			}
-			utils.Response(w, errorResponse, http.StatusBadRequest)
+// PatchBook handles PATCH /api/v1/books/{isbn}
+func (c *Controller) PatchBook(w http.ResponseWriter, req *http.Request) {
+	patchBookRequest := new(dto.PatchBookRequest)
+	if !decodeAndValidate(w, req, patchBookRequest) {
+		return
+	}
+	// Load the stored book so the fields missing in the patch keep their value
+	storedBook, err := c.getBookServiceInterface.GetBook(req.Context(), req.PathValue("isbn"))
+	if err != nil {
+		writeServiceError(w, req, c.logger, err, "Error updating book")
+		return
+	}
+	// mapper
+	bookDomain := dto.ApplyPatchBookRequest(storedBook, patchBookRequest)
+	c.updateBook(w, req, bookDomain)
+}
 
-			return
-		}
-	} else {
-		errorResponse := ErrorResponse{
-			Message: "Bad Request. Method not allowed. POST required",
-			Error:   "",
-		}
-		utils.Response(w, errorResponse, http.StatusBadRequest)
+func (c *Controller) updateBook(w http.ResponseWriter, req *http.Request, bookDomain *models.Book) {
+	// service
+	updatedBook, err := c.updateBookServiceInterface.UpdateBook(req.Context(), bookDomain)
+	if err != nil {
+		writeServiceError(w, req, c.logger, err, "Error updating book")
+		return
 	}
+	// response
+	utils.Response(w, dto.MapToUpdateBookResponse(updatedBook), http.StatusOK)
+}
 
+// DeleteBook handles DELETE /api/v1/books/{isbn}
+func (c *Controller) DeleteBook(w http.ResponseWriter, req *http.Request) {
+	// service
+	err := c.deleteBookServiceInterface.DeleteBook(req.Context(), req.PathValue("isbn"))
+	if err != nil {
+		writeServiceError(w, req, c.logger, err, "Error deleting book")
+		return
+	}
+	// response
+	w.WriteHeader(http.StatusNoContent)
 }
@@ -0,0 +1,18 @@
+package dto
+
+type CreateAuthorRequest struct {
+	Name string `json:"name" validate:"required,max=255"`
+}
+
+type AuthorResponse struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+type ListAuthorsResponse struct {
+	Authors []*AuthorResponse `json:"authors"`
+}
+
+type ListAuthorBooksResponse struct {
+	Books []*BookResponse `json:"books"`
+}
@@ -0,0 +1,40 @@
+package dto
+
+import "github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+
+func MapToAuthorModel(c *CreateAuthorRequest) *models.Author {
+	return &models.Author{
+		Name: c.Name,
+	}
+}
+
+func MapToAuthorResponse(m *models.Author) *AuthorResponse {
+	return &AuthorResponse{
+		ID:   m.ID,
+		Name: m.Name,
+	}
+}
+
+func MapToAuthorResponses(m []models.Author) []*AuthorResponse {
+	authors := make([]*AuthorResponse, 0, len(m))
+	for i := range m {
+		authors = append(authors, MapToAuthorResponse(&m[i]))
+	}
+	return authors
+}
+
+func MapToListAuthorsResponse(m []models.Author) *ListAuthorsResponse {
+	return &ListAuthorsResponse{
+		Authors: MapToAuthorResponses(m),
+	}
+}
+
+func MapToListAuthorBooksResponse(m []*models.Book) *ListAuthorBooksResponse {
+	books := make([]*BookResponse, 0, len(m))
+	for _, book := range m {
+		books = append(books, MapToBookResponse(book))
+	}
+	return &ListAuthorBooksResponse{
+		Books: books,
+	}
+}
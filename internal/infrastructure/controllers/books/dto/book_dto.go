@@ -0,0 +1,13 @@
+package dto
+
+// BookResponse is the representation of a book in the responses. The optional fields are omitted when unknown.
+type BookResponse struct {
+	Title           string            `json:"title"`
+	Isbn            string            `json:"isbn"`
+	Authors         []*AuthorResponse `json:"authors,omitempty"`
+	TotalPages      int               `json:"total_pages,omitempty"`
+	Publisher       string            `json:"publisher,omitempty"`
+	PublicationDate string            `json:"publication_date,omitempty"`
+	Language        string            `json:"language,omitempty"`
+	Description     string            `json:"description,omitempty"`
+}
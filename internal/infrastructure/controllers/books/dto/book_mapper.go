@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"strings"
+	"time"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/infrastructure/controllers/utils"
+)
+
+func MapToBookResponse(m *models.Book) *BookResponse {
+	response := &BookResponse{
+		Title:       m.Title,
+		Isbn:        m.Isbn,
+		Authors:     MapToAuthorResponses(m.Authors),
+		TotalPages:  m.TotalPages,
+		Publisher:   m.Publisher,
+		Language:    m.Language,
+		Description: m.Description,
+	}
+	if m.PublicationDate != nil {
+		response.PublicationDate = m.PublicationDate.Format(utils.DateLayout)
+	}
+	return response
+}
+
+// mapToAuthorReferences maps the author IDs of a payload, then the author names of its deprecated authors field, to the
+// authors of a book. The blank names are skipped.
+func mapToAuthorReferences(authorIds []uint, authorNames []string) []models.Author {
+	authors := make([]models.Author, 0, len(authorIds)+len(authorNames))
+	for _, id := range authorIds {
+		authors = append(authors, models.Author{ID: id})
+	}
+	for _, name := range authorNames {
+		if name = strings.TrimSpace(name); name != "" {
+			authors = append(authors, models.Author{Name: name})
+		}
+	}
+	return authors
+}
+
+// parseDate parses a date of a validated payload, an empty string is an unknown date
+func parseDate(date string) *time.Time {
+	if date == "" {
+		return nil
+	}
+	parsed, err := time.Parse(utils.DateLayout, date)
+	if err != nil {
+		return nil
+	}
+	return &parsed
+}
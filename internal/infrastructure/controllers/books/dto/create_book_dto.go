@@ -1,11 +1,16 @@
 package dto
 
 type CreateBookRequest struct {
-	Title string `json:"title"`
-	Isbn  string `json:"isbn"`
+	Title     string `json:"title" validate:"required,max=255"`
+	Isbn      string `json:"isbn" validate:"required,max=17" pattern:"^[0-9Xx -]+$"`
+	AuthorIds []uint `json:"author_ids" validate:"max=50"`
+	// Deprecated: use AuthorIds. The authors are found by name, or created.
+	Authors         []string `json:"authors" validate:"max=50"`
+	TotalPages      int      `json:"total_pages" validate:"min=0,max=100000"`
+	Publisher       string   `json:"publisher" validate:"max=255"`
+	PublicationDate string   `json:"publication_date" validate:"date"`
+	Language        string   `json:"language" validate:"max=35" pattern:"^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$"`
+	Description     string   `json:"description" validate:"max=5000"`
 }
 
-type CreateBookResponse struct {
-	Title string `json:"title"`
-	Isbn  string `json:"isbn"`
-}
+type CreateBookResponse = BookResponse
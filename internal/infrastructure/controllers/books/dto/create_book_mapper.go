@@ -4,14 +4,17 @@ import "github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/doma
 
 func MapToBookModel(c *CreateBookRequest) *models.Book {
 	return &models.Book{
-		Title: c.Title,
-		Isbn:  c.Isbn,
+		Title:           c.Title,
+		Isbn:            c.Isbn,
+		Authors:         mapToAuthorReferences(c.AuthorIds, c.Authors),
+		TotalPages:      c.TotalPages,
+		Publisher:       c.Publisher,
+		PublicationDate: parseDate(c.PublicationDate),
+		Language:        c.Language,
+		Description:     c.Description,
 	}
 }
 
 func MapToCreateBookResponse(m *models.Book) *CreateBookResponse {
-	return &CreateBookResponse{
-		Title: m.Title,
-		Isbn:  m.Isbn,
-	}
+	return MapToBookResponse(m)
 }
@@ -0,0 +1,7 @@
+package dto
+
+import "github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+
+func MapToGetBookResponse(m *models.Book) *GetBookResponse {
+	return MapToBookResponse(m)
+}
@@ -0,0 +1,23 @@
+package dto
+
+// ListBooksRequest holds the query parameters of the listing.
+// Sort is a field name, prefixed with "-" for descending order.
+type ListBooksRequest struct {
+	Title  string
+	Isbn   string
+	Sort   string
+	Limit  string
+	Cursor string
+}
+
+type ListBooksResponse struct {
+	Books      []*GetBookResponse `json:"books"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+type PaginationResponse struct {
+	TotalCount int64 `json:"total_count"`
+	Limit      int   `json:"limit"`
+	// NextCursor is null on the last page
+	NextCursor *string `json:"next_cursor"`
+}
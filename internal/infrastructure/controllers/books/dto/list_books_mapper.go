@@ -0,0 +1,58 @@
+package dto
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+)
+
+func MapToListBooksRequest(values url.Values) *ListBooksRequest {
+	return &ListBooksRequest{
+		Title:  values.Get("title"),
+		Isbn:   values.Get("isbn"),
+		Sort:   values.Get("sort"),
+		Limit:  values.Get("limit"),
+		Cursor: values.Get("cursor"),
+	}
+}
+
+func MapToBookListQuery(c *ListBooksRequest) (*models.BookListQuery, error) {
+	query := &models.BookListQuery{
+		TitleContains: c.Title,
+		IsbnPrefix:    c.Isbn,
+		Cursor:        c.Cursor,
+	}
+	if c.Limit != "" {
+		limit, err := strconv.Atoi(c.Limit)
+		if err != nil || limit < 1 {
+			return nil, fmt.Errorf("limit must be a positive integer, got %q", c.Limit)
+		}
+		query.Limit = limit
+	}
+	if c.Sort != "" {
+		query.Descending = strings.HasPrefix(c.Sort, "-")
+		query.SortBy = models.BookSortField(strings.TrimPrefix(c.Sort, "-"))
+	}
+	return query, nil
+}
+
+func MapToListBooksResponse(m *models.BookPage) *ListBooksResponse {
+	books := make([]*GetBookResponse, 0, len(m.Books))
+	for _, book := range m.Books {
+		books = append(books, MapToGetBookResponse(book))
+	}
+	response := &ListBooksResponse{
+		Books: books,
+		Pagination: PaginationResponse{
+			TotalCount: m.TotalCount,
+			Limit:      m.Limit,
+		},
+	}
+	if m.NextCursor != "" {
+		response.Pagination.NextCursor = &m.NextCursor
+	}
+	return response
+}
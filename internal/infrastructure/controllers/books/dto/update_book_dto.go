@@ -0,0 +1,29 @@
+package dto
+
+// UpdateBookRequest is the PUT payload, every field replaces the stored value
+type UpdateBookRequest struct {
+	Title     string `json:"title" validate:"required,max=255"`
+	AuthorIds []uint `json:"author_ids" validate:"max=50"`
+	// Deprecated: use AuthorIds. The authors are found by name, or created.
+	Authors         []string `json:"authors" validate:"max=50"`
+	TotalPages      int      `json:"total_pages" validate:"min=0,max=100000"`
+	Publisher       string   `json:"publisher" validate:"max=255"`
+	PublicationDate string   `json:"publication_date" validate:"date"`
+	Language        string   `json:"language" validate:"max=35" pattern:"^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$"`
+	Description     string   `json:"description" validate:"max=5000"`
+}
+
+// PatchBookRequest is the PATCH payload, only the fields present are changed
+type PatchBookRequest struct {
+	Title     *string `json:"title" validate:"notblank,max=255"`
+	AuthorIds *[]uint `json:"author_ids" validate:"max=50"`
+	// Deprecated: use AuthorIds. The authors are found by name, or created.
+	Authors         *[]string `json:"authors" validate:"max=50"`
+	TotalPages      *int      `json:"total_pages" validate:"min=0,max=100000"`
+	Publisher       *string   `json:"publisher" validate:"max=255"`
+	PublicationDate *string   `json:"publication_date" validate:"date"`
+	Language        *string   `json:"language" validate:"max=35" pattern:"^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$"`
+	Description     *string   `json:"description" validate:"max=5000"`
+}
+
+type UpdateBookResponse = BookResponse
@@ -0,0 +1,55 @@
+package dto
+
+import "github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+
+func MapUpdateBookRequestToBookModel(isbn string, c *UpdateBookRequest) *models.Book {
+	return &models.Book{
+		Title:           c.Title,
+		Isbn:            isbn,
+		Authors:         mapToAuthorReferences(c.AuthorIds, c.Authors),
+		TotalPages:      c.TotalPages,
+		Publisher:       c.Publisher,
+		PublicationDate: parseDate(c.PublicationDate),
+		Language:        c.Language,
+		Description:     c.Description,
+	}
+}
+
+// ApplyPatchBookRequest returns a copy of m with the fields present in the patch applied
+func ApplyPatchBookRequest(m *models.Book, c *PatchBookRequest) *models.Book {
+	book := *m
+	if c.Title != nil {
+		book.Title = *c.Title
+	}
+	if c.AuthorIds != nil || c.Authors != nil {
+		var authorIds []uint
+		var authorNames []string
+		if c.AuthorIds != nil {
+			authorIds = *c.AuthorIds
+		}
+		if c.Authors != nil {
+			authorNames = *c.Authors
+		}
+		book.Authors = mapToAuthorReferences(authorIds, authorNames)
+	}
+	if c.TotalPages != nil {
+		book.TotalPages = *c.TotalPages
+	}
+	if c.Publisher != nil {
+		book.Publisher = *c.Publisher
+	}
+	if c.PublicationDate != nil {
+		book.PublicationDate = parseDate(*c.PublicationDate)
+	}
+	if c.Language != nil {
+		book.Language = *c.Language
+	}
+	if c.Description != nil {
+		book.Description = *c.Description
+	}
+	return &book
+}
+
+func MapToUpdateBookResponse(m *models.Book) *UpdateBookResponse {
+	return MapToBookResponse(m)
+}
@@ -0,0 +1,156 @@
+package books
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	servicebook "github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/application/services/books"
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/infrastructure/controllers/books/dto"
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/infrastructure/controllers/utils"
+)
+
+type errorMapping struct {
+	err        error
+	statusCode int
+	code       string
+}
+
+// serviceErrorMappings maps the errors returned by the services to the response. The first match wins.
+var serviceErrorMappings = []errorMapping{
+	{err: servicebook.ErrBookNotFound, statusCode: http.StatusNotFound, code: ErrorCodeBookNotFound},
+	{err: servicebook.ErrBookAlreadyExists, statusCode: http.StatusConflict, code: ErrorCodeBookAlreadyExists},
+	{err: servicebook.ErrAuthorNotFound, statusCode: http.StatusNotFound, code: ErrorCodeAuthorNotFound},
+	{err: servicebook.ErrUnknownAuthor, statusCode: http.StatusUnprocessableEntity, code: ErrorCodeUnknownAuthor},
+	{err: servicebook.ErrInvalidIsbn, statusCode: http.StatusUnprocessableEntity, code: ErrorCodeInvalidIsbn},
+	{err: servicebook.ErrInvalidListQuery, statusCode: http.StatusBadRequest, code: ErrorCodeInvalidListQuery},
+	{err: servicebook.ErrUpstreamUnavailable, statusCode: http.StatusServiceUnavailable, code: ErrorCodeUpstreamUnavailable},
+	{err: servicebook.ErrUpstreamBadResponse, statusCode: http.StatusBadGateway, code: ErrorCodeUpstreamBadResponse},
+	// The request took longer than the timeout of its route
+	{err: context.DeadlineExceeded, statusCode: http.StatusServiceUnavailable, code: ErrorCodeRequestTimeout},
+}
+
+// writeServiceError sends the error response for an error returned by a service.
+// Unknown errors are internal server errors. The server errors are logged, the client errors only in debug.
+func writeServiceError(w http.ResponseWriter, req *http.Request, logger *slog.Logger, err error, message string) {
+	statusCode, code := http.StatusInternalServerError, ErrorCodeInternal
+	for _, mapping := range serviceErrorMappings {
+		if errors.Is(err, mapping.err) {
+			statusCode, code = mapping.statusCode, mapping.code
+			break
+		}
+	}
+	// The timeout of the route cut the request, e.g. while waiting for an upstream service
+	if errors.Is(err, context.DeadlineExceeded) && errors.Is(req.Context().Err(), context.DeadlineExceeded) {
+		statusCode, code = http.StatusServiceUnavailable, ErrorCodeRequestTimeout
+	}
+	level := slog.LevelDebug
+	if statusCode >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+	logger.Log(req.Context(), level, message, slog.String("code", code), slog.Any("error", err))
+	writeError(w, req, statusCode, code, message, err)
+}
+
+// WriteError sends an error response outside of the controllers, e.g. from a middleware
+func WriteError(w http.ResponseWriter, req *http.Request, statusCode int, code string, message string) {
+	writeError(w, req, statusCode, code, message, nil)
+}
+
+// writeError sends an ErrorResponse, or a ProblemDetails when the client accepts application/problem+json.
+// err can be nil.
+func writeError(w http.ResponseWriter, req *http.Request, statusCode int, code string, message string, err error) {
+	writeErrorWithFields(w, req, statusCode, code, message, err, nil)
+}
+
+// writeValidationError sends a 422 listing every invalid field
+func writeValidationError(w http.ResponseWriter, req *http.Request, validationError *utils.ValidationError) {
+	writeErrorWithFields(w, req, http.StatusUnprocessableEntity, ErrorCodeValidationFailed, "Invalid payload",
+		validationError, validationError.Errors)
+}
+
+func writeErrorWithFields(w http.ResponseWriter, req *http.Request, statusCode int, code string, message string,
+	err error, fieldErrors []utils.FieldError) {
+	if utils.AcceptsProblemJSON(req) {
+		problem := newProblemDetails(req, statusCode, code, message, err)
+		problem.Errors = fieldErrors
+		utils.ProblemResponse(w, problem)
+		return
+	}
+	errorResponse := ErrorResponse{
+		Code:    code,
+		Message: http.StatusText(statusCode) + ". " + message,
+		Errors:  fieldErrors,
+	}
+	if err != nil {
+		errorResponse.Error = err.Error()
+	}
+	utils.Response(w, errorResponse, statusCode)
+}
+
+// CheckValidationRules returns an error when the validation tags of a request payload are broken, so the app refuses to
+// start instead of failing the requests
+func CheckValidationRules() error {
+	return utils.CheckRules(dto.CreateBookRequest{}, dto.UpdateBookRequest{}, dto.PatchBookRequest{}, dto.CreateAuthorRequest{})
+}
+
+// decodeAndValidate decodes the JSON body of the request into val and validates it.
+// It sends the error response and returns false when the payload is not valid.
+// All the invalid fields are reported together.
+func decodeAndValidate(w http.ResponseWriter, req *http.Request, val interface{}) bool {
+	validationError := &utils.ValidationError{}
+	err := utils.Decode(req, val)
+	switch {
+	case errors.As(err, &validationError):
+	case errors.Is(err, utils.ErrBodyTooLarge):
+		writeError(w, req, http.StatusRequestEntityTooLarge, ErrorCodePayloadTooLarge, "Invalid payload", err)
+		return false
+	case err != nil:
+		writeError(w, req, http.StatusBadRequest, ErrorCodeInvalidPayload, "Invalid payload", err)
+		return false
+	}
+	// The fields with a wrong type are only reported once
+	reported := map[string]bool{}
+	for _, fieldError := range validationError.Errors {
+		reported[fieldError.Field] = true
+	}
+	var rulesError *utils.ValidationError
+	if err := utils.Validate(val); err != nil && !errors.As(err, &rulesError) {
+		// The validation tags of the payload are broken
+		writeError(w, req, http.StatusInternalServerError, ErrorCodeInternal, "Invalid validation rules", err)
+		return false
+	}
+	if rulesError != nil {
+		for _, fieldError := range rulesError.Errors {
+			if !reported[fieldError.Field] {
+				validationError.Errors = append(validationError.Errors, fieldError)
+			}
+		}
+	}
+	if len(validationError.Errors) > 0 {
+		writeValidationError(w, req, validationError)
+		return false
+	}
+	return true
+}
+
+// newProblemDetails builds the problem of an error code. The type and title only depend on the code,
+// e.g. BOOK_NOT_FOUND is "/problems/book-not-found" and "Book not found".
+func newProblemDetails(req *http.Request, statusCode int, code string, message string, err error) utils.ProblemDetails {
+	words := strings.ToLower(code)
+	title := strings.ReplaceAll(words, "_", " ")
+	detail := message
+	if err != nil {
+		detail += ": " + err.Error()
+	}
+	return utils.ProblemDetails{
+		Type:     "/problems/" + strings.ReplaceAll(words, "_", "-"),
+		Title:    strings.ToUpper(title[:1]) + title[1:],
+		Status:   statusCode,
+		Detail:   detail,
+		Instance: req.URL.RequestURI(),
+		Code:     code,
+	}
+}
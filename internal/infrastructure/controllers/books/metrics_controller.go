@@ -0,0 +1,33 @@
+package books
+
+import (
+	"net/http"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/infrastructure/controllers/utils"
+)
+
+// MetricsController exposes the counters of the application, such as the hits of the caches, as JSON
+type MetricsController struct {
+	metrics map[string]func() interface{}
+}
+
+func NewMetricsController() *MetricsController {
+	return &MetricsController{
+		metrics: map[string]func() interface{}{},
+	}
+}
+
+// Register adds a metric, read on every request
+func (c *MetricsController) Register(name string, metric func() interface{}) {
+	c.metrics[name] = metric
+}
+
+// Metrics handles GET /internal/metrics
+func (c *MetricsController) Metrics(w http.ResponseWriter, req *http.Request) {
+	// response
+	metrics := make(map[string]interface{}, len(c.metrics))
+	for name, metric := range c.metrics {
+		metrics[name] = metric()
+	}
+	utils.Response(w, metrics, http.StatusOK)
+}
@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/infrastructure/requestid"
+)
+
+type CORSOptions struct {
+	// AllowedOrigins are the origins allowed to call the API, "*" allows any. CORS is disabled when empty.
+	AllowedOrigins []string
+	AllowedMethods []string
+	AllowedHeaders []string
+	// MaxAge is how long the browsers cache a preflight response
+	MaxAge time.Duration
+}
+
+// CORS answers the preflight requests and adds the CORS headers to the responses of the allowed origins.
+// The requests of the other origins are served without them, so the browsers block the responses.
+func CORS(options CORSOptions) Middleware {
+	allowedMethods := strings.Join(options.AllowedMethods, ", ")
+	allowedHeaders := strings.Join(options.AllowedHeaders, ", ")
+	return func(next http.Handler) http.Handler {
+		if len(options.AllowedOrigins) == 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			origin := req.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, req)
+				return
+			}
+			w.Header().Add("Vary", "Origin")
+			if !slices.Contains(options.AllowedOrigins, "*") && !slices.Contains(options.AllowedOrigins, origin) {
+				next.ServeHTTP(w, req)
+				return
+			}
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			if req.Method == http.MethodOptions && req.Header.Get("Access-Control-Request-Method") != "" {
+				w.Header().Set("Access-Control-Allow-Methods", allowedMethods)
+				w.Header().Set("Access-Control-Allow-Headers", allowedHeaders)
+				w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(options.MaxAge.Seconds())))
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			w.Header().Set("Access-Control-Expose-Headers", requestid.Header)
+			next.ServeHTTP(w, req)
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"runtime/debug"
+	"time"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/infrastructure/controllers/books"
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/infrastructure/logging"
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/infrastructure/requestid"
+)
+
+// Middleware wraps a handler with a cross-cutting concern
+type Middleware func(http.Handler) http.Handler
+
+// Chain wraps handler with the middlewares, the first one being the outermost
+func Chain(handler http.Handler, middlewares ...Middleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// validRequestID rejects the IDs sent by the clients that could break the logs or the headers
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._\-]{1,128}$`)
+
+// RequestID takes the ID of the request from the X-Request-ID header, or generates it, and echoes it in the response.
+// It is added to the context, so the records logged while serving the request and the outbound requests have it.
+func RequestID() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			id := req.Header.Get(requestid.Header)
+			if !validRequestID.MatchString(id) {
+				id = requestid.New()
+			}
+			w.Header().Set(requestid.Header, id)
+			ctx := requestid.NewContext(req.Context(), id)
+			ctx = logging.WithAttrs(ctx, slog.String("request_id", id))
+			next.ServeHTTP(w, req.WithContext(ctx))
+		})
+	}
+}
+
+// AccessLog logs every request once it is served. The method and the path are added to the context of the request,
+// so every record logged while serving it has them.
+func AccessLog(logger *slog.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			start := time.Now()
+			ctx := logging.WithAttrs(req.Context(),
+				slog.String("method", req.Method),
+				slog.String("path", req.URL.Path),
+			)
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(recorder, req.WithContext(ctx))
+			level := slog.LevelInfo
+			if recorder.status >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+			logger.LogAttrs(ctx, level, "Request served",
+				slog.Int("status", recorder.status),
+				slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			)
+		})
+	}
+}
+
+// Recovery turns a panic of a handler into a 500 ErrorResponse, logged with its stack trace
+func Recovery(logger *slog.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			recorder := &statusRecorder{ResponseWriter: w}
+			defer func() {
+				recovered := recover()
+				if recovered == nil {
+					return
+				}
+				// The server aborts the response on purpose, it must reach it
+				if err, ok := recovered.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+					panic(recovered)
+				}
+				logger.ErrorContext(req.Context(), "Panic serving request",
+					slog.Any("panic", recovered), slog.String("stack", string(debug.Stack())))
+				if recorder.wroteHeader {
+					// Too late for an error response, the client sees a truncated one
+					return
+				}
+				books.WriteError(recorder, req, http.StatusInternalServerError, books.ErrorCodeInternal, "Unexpected error")
+			}()
+			next.ServeHTTP(recorder, req)
+		})
+	}
+}
+
+// Timeout cancels the context of the request after timeout, so its queries and its outbound requests are cancelled
+// and it is answered with a 503 REQUEST_TIMEOUT. A zero timeout disables it.
+func Timeout(timeout time.Duration) Middleware {
+	return func(next http.Handler) http.Handler {
+		if timeout <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			ctx, cancel := context.WithTimeout(req.Context(), timeout)
+			defer cancel()
+			next.ServeHTTP(w, req.WithContext(ctx))
+		})
+	}
+}
+
+// statusRecorder remembers the status code of the response
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -2,10 +2,82 @@ package controllers
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/infrastructure/controllers/books"
 )
 
-func SetupRoutes(bookController *books.Controller) {
-	http.HandleFunc("/api/v1/createBook", bookController.CreateBook)
+type RouterOptions struct {
+	// Timeout is the timeout of the routes without their own
+	Timeout time.Duration
+	// RouteTimeouts overrides the timeout of some routes, by pattern, e.g. "POST /api/v1/books"
+	RouteTimeouts map[string]time.Duration
+}
+
+// SetupRoutes returns the router of the API. Every call returns a new router, so several apps can run in one process.
+func SetupRoutes(options RouterOptions, bookController *books.Controller, authorController *books.AuthorController, metricsController *books.MetricsController) http.Handler {
+	mux := http.NewServeMux()
+	handle := func(pattern string, handler http.HandlerFunc) {
+		timeout, ok := options.RouteTimeouts[pattern]
+		if !ok {
+			timeout = options.Timeout
+		}
+		mux.Handle(pattern, Timeout(timeout)(handler))
+	}
+	// books
+	handle("POST /api/v1/books", bookController.CreateBook)
+	handle("POST /api/v1/createBook", bookController.CreateBook) // Deprecated, kept for the existing clients
+	handle("GET /api/v1/books", bookController.ListBooks)
+	handle("GET /api/v1/books/{isbn}", bookController.GetBook)
+	handle("PUT /api/v1/books/{isbn}", bookController.UpdateBook)
+	handle("PATCH /api/v1/books/{isbn}", bookController.PatchBook)
+	handle("DELETE /api/v1/books/{isbn}", bookController.DeleteBook)
+	// authors
+	handle("POST /api/v1/authors", authorController.CreateAuthor)
+	handle("GET /api/v1/authors", authorController.ListAuthors)
+	handle("GET /api/v1/authors/{id}", authorController.GetAuthor)
+	handle("GET /api/v1/authors/{id}/books", authorController.ListAuthorBooks)
+	// internal
+	handle("GET /internal/metrics", metricsController.Metrics)
+	return &router{mux: mux}
+}
+
+// router answers the requests without a route with an ErrorResponse, instead of the plain text of http.ServeMux
+type router struct {
+	mux *http.ServeMux
+}
+
+func (r *router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if handler, pattern := r.mux.Handler(req); pattern == "" {
+		// Let the mux decide between a 404 and a 405, the latter with its Allow header
+		recorder := &headerRecorder{header: http.Header{}}
+		handler.ServeHTTP(recorder, req)
+		if recorder.status == http.StatusMethodNotAllowed {
+			w.Header().Set("Allow", recorder.header.Get("Allow"))
+			books.WriteError(w, req, http.StatusMethodNotAllowed, books.ErrorCodeMethodNotAllowed,
+				recorder.header.Get("Allow")+" required")
+			return
+		}
+		books.WriteError(w, req, http.StatusNotFound, books.ErrorCodeNotFound, "No route for "+req.URL.Path)
+		return
+	}
+	r.mux.ServeHTTP(w, req)
+}
+
+// headerRecorder keeps the headers and the status of a response and drops its body
+type headerRecorder struct {
+	header http.Header
+	status int
+}
+
+func (r *headerRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *headerRecorder) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (r *headerRecorder) WriteHeader(status int) {
+	r.status = status
 }
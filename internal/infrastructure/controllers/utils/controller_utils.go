@@ -1,16 +1,57 @@
 package utils
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 )
 
+// MaxBodyBytes is the maximum size of a request body accepted by Decode
+const MaxBodyBytes = 1 << 20
+
+// ErrBodyTooLarge is returned by Decode when the body is bigger than MaxBodyBytes
+var ErrBodyTooLarge = fmt.Errorf("request body must not be larger than %d bytes", MaxBodyBytes)
+
 // Decode reads the body of an HTTP request looking for a JSON document. The
 // body is decoded into the provided value.
+// It returns a *ValidationError when the document has fields that val does not have
+// or fields of the wrong type. In that case val is still decoded, so it can be validated as well.
 func Decode(r *http.Request, val interface{}) error {
-	if err := json.NewDecoder(r.Body).Decode(val); err != nil {
+	body, err := io.ReadAll(http.MaxBytesReader(nil, r.Body, MaxBodyBytes))
+	if err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			return ErrBodyTooLarge
+		}
 		return err
 	}
+	validationError := &ValidationError{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	if err := decoder.Decode(val); err != nil {
+		var typeError *json.UnmarshalTypeError
+		if !errors.As(err, &typeError) {
+			return err
+		}
+		validationError.Errors = append(validationError.Errors, FieldError{
+			Field:   typeError.Field,
+			Message: "must be a " + typeError.Type.String(),
+		})
+	}
+	if decoder.More() {
+		return errors.New("request body must only contain a single JSON document")
+	}
+	for _, field := range unknownFields(body, val) {
+		validationError.Errors = append(validationError.Errors, FieldError{
+			Field:   field,
+			Message: "unknown field",
+		})
+	}
+	if len(validationError.Errors) > 0 {
+		return validationError
+	}
 	return nil
 }
 
@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// ProblemJSONContentType is the media type of ProblemDetails, RFC 7807
+const ProblemJSONContentType = "application/problem+json"
+
+// ProblemDetails is an error response as defined by RFC 7807. Code is an extension member.
+type ProblemDetails struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     string       `json:"code,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError is a problem with one field of the request
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// AcceptsProblemJSON reports whether the client asked for ProblemDetails in the Accept header
+func AcceptsProblemJSON(r *http.Request) bool {
+	for _, accept := range r.Header.Values("Accept") {
+		for _, mediaRange := range strings.Split(accept, ",") {
+			mediaType, params, err := mime.ParseMediaType(mediaRange)
+			if err != nil {
+				continue
+			}
+			// q=0 means not acceptable
+			if mediaType == ProblemJSONContentType && params["q"] != "0" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ProblemResponse converts a ProblemDetails to JSON and sends it to the client.
+func ProblemResponse(w http.ResponseWriter, problem ProblemDetails) error {
+
+	// Convert the response value to JSON.
+	res, err := json.Marshal(problem)
+	if err != nil {
+		return err
+	}
+
+	// Respond with the provided JSON.
+	w.Header().Set("Content-Type", ProblemJSONContentType)
+	w.WriteHeader(problem.Status)
+	if _, err := w.Write(res); err != nil {
+		return err
+	}
+
+	return nil
+}
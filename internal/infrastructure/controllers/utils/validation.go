@@ -0,0 +1,297 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// DateLayout is the format of the dates in the payloads
+const DateLayout = time.DateOnly
+
+// ValidationError holds all the problems found in a request payload
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, fieldError := range e.Errors {
+		messages = append(messages, fieldError.Field+": "+fieldError.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Validate checks the struct fields of val against their rules, declared in the struct tags:
+//
+//	Title string `json:"title" validate:"required,max=255"`
+//	Isbn  string `json:"isbn" validate:"required" pattern:"^[0-9X-]+$"`
+//
+// The validate tag accepts:
+//   - required: strings must not be blank, numbers must not be zero, slices must not be empty and pointers must not be nil.
+//   - notblank: strings must not be blank, unlike required a nil pointer is accepted, e.g. a PATCH field not sent.
+//   - min=N and max=N: bounds of the length of strings and slices, or of the value of numbers.
+//   - date: non-empty strings must be a date formatted as DateLayout.
+//
+// The pattern tag is a regular expression that non-empty strings must match.
+// Nil pointers are only checked by required, so optional PATCH fields can be declared as pointers.
+// It returns a *ValidationError with one FieldError per broken rule, or nil.
+// It returns another error when the tags of val are broken, see CheckRules.
+func Validate(val interface{}) error {
+	value := reflect.ValueOf(val)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+	fields, err := rulesOf(value.Type())
+	if err != nil {
+		return err
+	}
+	validationError := &ValidationError{}
+	for _, field := range fields {
+		for _, message := range field.validate(value.Field(field.index)) {
+			validationError.Errors = append(validationError.Errors, FieldError{Field: field.name, Message: message})
+		}
+	}
+	if len(validationError.Errors) > 0 {
+		return validationError
+	}
+	return nil
+}
+
+// CheckRules returns an error when the tags of a struct are broken: an unknown rule, a min or max rule without an
+// integer argument, or a pattern that does not compile. Call it at startup with the payloads validated later.
+func CheckRules(vals ...interface{}) error {
+	var errs []error
+	for _, val := range vals {
+		valueType := reflect.TypeOf(val)
+		for valueType != nil && valueType.Kind() == reflect.Ptr {
+			valueType = valueType.Elem()
+		}
+		if valueType == nil || valueType.Kind() != reflect.Struct {
+			continue
+		}
+		if _, err := rulesOf(valueType); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// fieldRules are the parsed rules of a struct field
+type fieldRules struct {
+	index    int
+	name     string
+	required bool
+	notBlank bool
+	date     bool
+	min, max *int
+	pattern  *regexp.Regexp
+}
+
+// typeRules caches the rules of the struct types, or the error of their tags
+var typeRules sync.Map
+
+type parsedRules struct {
+	fields []fieldRules
+	err    error
+}
+
+// rulesOf parses the tags of the fields of a struct type once
+func rulesOf(valueType reflect.Type) ([]fieldRules, error) {
+	if parsed, ok := typeRules.Load(valueType); ok {
+		return parsed.(parsedRules).fields, parsed.(parsedRules).err
+	}
+	var fields []fieldRules
+	var errs []error
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := jsonFieldName(field)
+		if name == "" {
+			continue
+		}
+		rules, err := parseRules(field.Tag)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("validate: field %s of %s: %w", field.Name, valueType, err))
+			continue
+		}
+		rules.index, rules.name = i, name
+		fields = append(fields, rules)
+	}
+	parsed := parsedRules{fields: fields, err: errors.Join(errs...)}
+	typeRules.Store(valueType, parsed)
+	return parsed.fields, parsed.err
+}
+
+func parseRules(tag reflect.StructTag) (fieldRules, error) {
+	var rules fieldRules
+	if validate := tag.Get("validate"); validate != "" {
+		for _, rule := range strings.Split(validate, ",") {
+			name, argument, _ := strings.Cut(rule, "=")
+			switch name {
+			case "required":
+				rules.required = true
+			case "notblank":
+				rules.notBlank = true
+			case "date":
+				rules.date = true
+			case "min", "max":
+				bound, err := strconv.Atoi(argument)
+				if err != nil {
+					return rules, fmt.Errorf("rule %q must have an integer argument", rule)
+				}
+				if name == "min" {
+					rules.min = &bound
+				} else {
+					rules.max = &bound
+				}
+			default:
+				return rules, fmt.Errorf("unknown rule %q", rule)
+			}
+		}
+	}
+	if pattern := tag.Get("pattern"); pattern != "" {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return rules, fmt.Errorf("invalid pattern: %w", err)
+		}
+		rules.pattern = compiled
+	}
+	return rules, nil
+}
+
+func (r fieldRules) validate(value reflect.Value) []string {
+	var messages []string
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			if r.required {
+				return []string{"is required"}
+			}
+			return nil
+		}
+		value = value.Elem()
+	}
+	if r.required && isBlank(value) {
+		messages = append(messages, "is required")
+	}
+	if r.notBlank && value.Kind() == reflect.String && isBlank(value) {
+		messages = append(messages, "must not be blank")
+	}
+	if r.date && value.Kind() == reflect.String && value.String() != "" {
+		if _, err := time.Parse(DateLayout, value.String()); err != nil {
+			messages = append(messages, "must be a valid date formatted as YYYY-MM-DD")
+		}
+	}
+	if r.min != nil {
+		if message := checkBound(value, "min", *r.min); message != "" {
+			messages = append(messages, message)
+		}
+	}
+	if r.max != nil {
+		if message := checkBound(value, "max", *r.max); message != "" {
+			messages = append(messages, message)
+		}
+	}
+	if r.pattern != nil && value.Kind() == reflect.String && value.String() != "" {
+		if !r.pattern.MatchString(value.String()) {
+			messages = append(messages, "must match the pattern "+r.pattern.String())
+		}
+	}
+	return messages
+}
+
+func isBlank(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.String:
+		return strings.TrimSpace(value.String()) == ""
+	case reflect.Slice, reflect.Map:
+		return value.Len() == 0
+	default:
+		return value.IsZero()
+	}
+}
+
+// checkBound returns the message of the broken min or max rule, or an empty string
+func checkBound(value reflect.Value, rule string, bound int) string {
+	var actual int64
+	var unit string
+	switch value.Kind() {
+	case reflect.String:
+		actual, unit = int64(utf8.RuneCountInString(value.String())), " character"
+	case reflect.Slice, reflect.Map:
+		actual, unit = int64(value.Len()), " item"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		actual = value.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		actual = int64(value.Uint())
+	default:
+		return ""
+	}
+	if unit != "" && bound != 1 {
+		unit += "s"
+	}
+	if rule == "min" && actual < int64(bound) {
+		return fmt.Sprintf("must be at least %d%s", bound, unit)
+	}
+	if rule == "max" && actual > int64(bound) {
+		return fmt.Sprintf("must be at most %d%s", bound, unit)
+	}
+	return ""
+}
+
+// jsonFieldName returns the name of the field in the JSON document, or an empty string when it is not decoded
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// unknownFields returns the sorted names of the top level fields of the JSON object in body that val does not have
+func unknownFields(body []byte, val interface{}) []string {
+	valueType := reflect.TypeOf(val)
+	for valueType != nil && valueType.Kind() == reflect.Ptr {
+		valueType = valueType.Elem()
+	}
+	if valueType == nil || valueType.Kind() != reflect.Struct {
+		return nil
+	}
+	var document map[string]json.RawMessage
+	if err := json.Unmarshal(body, &document); err != nil {
+		return nil
+	}
+	var unknown []string
+	for key := range document {
+		known := false
+		for i := 0; i < valueType.NumField() && !known; i++ {
+			field := valueType.Field(i)
+			// encoding/json matches the names case-insensitively
+			known = field.IsExported() && strings.EqualFold(jsonFieldName(field), key)
+		}
+		if !known {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
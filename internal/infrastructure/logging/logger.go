@@ -0,0 +1,70 @@
+// Package logging builds the structured logger of the application.
+//
+// The logs are JSON lines written with log/slog. The attributes added to a context with WithAttrs, such as the
+// request ID, are added to every record logged with that context, and the secrets are redacted by Redact.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// New returns a JSON logger of the records at level or above
+func New(w io.Writer, level slog.Level) *slog.Logger {
+	handler := slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: Redact,
+	})
+	return slog.New(&contextHandler{Handler: handler})
+}
+
+// ParseLevel parses debug, info, warn or error, ignoring the case
+func ParseLevel(level string) (slog.Level, error) {
+	var parsed slog.Level
+	err := parsed.UnmarshalText([]byte(strings.TrimSpace(level)))
+	return parsed, err
+}
+
+type contextAttrsKey struct{}
+
+// WithAttrs returns a copy of ctx whose records also have attrs
+func WithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	existing, _ := ctx.Value(contextAttrsKey{}).([]slog.Attr)
+	merged := make([]slog.Attr, 0, len(existing)+len(attrs))
+	merged = append(merged, existing...)
+	merged = append(merged, attrs...)
+	return context.WithValue(ctx, contextAttrsKey{}, merged)
+}
+
+// contextHandler adds the attributes of the context to the records
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if attrs, ok := ctx.Value(contextAttrsKey{}).([]slog.Attr); ok {
+		record.AddAttrs(attrs...)
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+// PrintfWriter adapts the logger to the libraries that log with Printf, such as gorm
+type PrintfWriter struct {
+	Logger *slog.Logger
+	Level  slog.Level
+}
+
+func (w PrintfWriter) Printf(format string, args ...interface{}) {
+	w.Logger.Log(context.Background(), w.Level, strings.TrimSpace(fmt.Sprintf(format, args...)))
+}
@@ -0,0 +1,14 @@
+package book
+
+import (
+	"gorm.io/gorm"
+)
+
+type AuthorEntity struct {
+	gorm.Model
+	Name string `gorm:"column:name"`
+}
+
+func (AuthorEntity) TableName() string {
+	return "myschema.authors"
+}
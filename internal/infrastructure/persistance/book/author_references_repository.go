@@ -0,0 +1,63 @@
+package book
+
+import (
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+	"gorm.io/gorm"
+)
+
+func selectAuthorsByIds(database *gorm.DB, ids []uint) ([]models.Author, error) {
+	var authorEntities []AuthorEntity
+	result := database.Where("id IN ?", ids).Find(&authorEntities)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return mapToAuthorModels(authorEntities), nil
+}
+
+// resolveAuthorNames replaces the authors referenced by name, without an ID, with the first author with the name,
+// inserting the missing ones. It runs in the transaction writing the book, so the new authors are rolled back with it.
+func resolveAuthorNames(tx *gorm.DB, references []models.Author) ([]models.Author, error) {
+	authors := make([]models.Author, 0, len(references))
+	seen := make(map[uint]bool, len(references))
+	for _, reference := range references {
+		author := reference
+		if reference.ID == 0 {
+			var authorEntities []AuthorEntity
+			result := tx.Where("name = ?", reference.Name).Order("id").Limit(1).Find(&authorEntities)
+			if result.Error != nil {
+				return nil, result.Error
+			}
+			if len(authorEntities) == 0 {
+				authorEntity := mapToAuthorEntity(&models.Author{Name: reference.Name})
+				if result := tx.Create(authorEntity); result.Error != nil {
+					return nil, result.Error
+				}
+				authorEntities = append(authorEntities, *authorEntity)
+			}
+			author = *mapToAuthorModel(&authorEntities[0])
+		}
+		// A name may be the name of an author also referenced by ID
+		if !seen[author.ID] {
+			seen[author.ID] = true
+			authors = append(authors, author)
+		}
+	}
+	return authors, nil
+}
+
+func selectAuthorById(database *gorm.DB, id uint) (*models.Author, error) {
+	var authorEntities []AuthorEntity
+	result := database.Where("id = ?", id).Limit(1).Find(&authorEntities)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(authorEntities) == 0 {
+		return nil, nil
+	}
+	return mapToAuthorModel(&authorEntities[0]), nil
+}
+
+// orderAuthors sorts the preloaded authors of a book
+func orderAuthors(database *gorm.DB) *gorm.DB {
+	return database.Order("id")
+}
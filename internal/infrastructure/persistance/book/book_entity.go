@@ -1,14 +1,23 @@
 package book
 
 import (
+	"time"
+
 	"gorm.io/gorm"
 )
 
 type BookEntity struct {
 	gorm.Model
-	// Isbn: International Standard Book Number
+	// Isbn: International Standard Book Number, stored as the canonical ISBN-13
 	Isbn  string `gorm:"column:isbn;unique"`
 	Title string `gorm:"column:title"`
+	// Authors are linked through the myschema.book_authors join table
+	Authors         []AuthorEntity `gorm:"many2many:book_authors;joinForeignKey:BookID;joinReferences:AuthorID"`
+	TotalPages      int            `gorm:"column:total_pages"`
+	Publisher       string         `gorm:"column:publisher"`
+	PublicationDate *time.Time     `gorm:"column:publication_date;type:date"`
+	Language        string         `gorm:"column:language"`
+	Description     string         `gorm:"column:description"`
 }
 
 func (BookEntity) TableName() string {
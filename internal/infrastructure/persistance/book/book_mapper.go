@@ -0,0 +1,68 @@
+package book
+
+import "github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+
+func mapToBookEntity(book *models.Book) *BookEntity {
+	authors := make([]AuthorEntity, 0, len(book.Authors))
+	for i := range book.Authors {
+		authors = append(authors, *mapToAuthorEntity(&book.Authors[i]))
+	}
+	return &BookEntity{
+		Isbn:            book.Isbn,
+		Title:           book.Title,
+		Authors:         authors,
+		TotalPages:      book.TotalPages,
+		Publisher:       book.Publisher,
+		PublicationDate: book.PublicationDate,
+		Language:        book.Language,
+		Description:     book.Description,
+	}
+}
+
+func mapToBookModel(bookEntity *BookEntity) *models.Book {
+	authors := make([]models.Author, 0, len(bookEntity.Authors))
+	for i := range bookEntity.Authors {
+		authors = append(authors, *mapToAuthorModel(&bookEntity.Authors[i]))
+	}
+	return &models.Book{
+		Isbn:            bookEntity.Isbn,
+		Title:           bookEntity.Title,
+		Authors:         authors,
+		TotalPages:      bookEntity.TotalPages,
+		Publisher:       bookEntity.Publisher,
+		PublicationDate: bookEntity.PublicationDate,
+		Language:        bookEntity.Language,
+		Description:     bookEntity.Description,
+	}
+}
+
+func mapToBookModels(bookEntities []BookEntity) []*models.Book {
+	books := make([]*models.Book, 0, len(bookEntities))
+	for i := range bookEntities {
+		books = append(books, mapToBookModel(&bookEntities[i]))
+	}
+	return books
+}
+
+func mapToAuthorEntity(author *models.Author) *AuthorEntity {
+	authorEntity := &AuthorEntity{
+		Name: author.Name,
+	}
+	authorEntity.ID = author.ID
+	return authorEntity
+}
+
+func mapToAuthorModel(authorEntity *AuthorEntity) *models.Author {
+	return &models.Author{
+		ID:   authorEntity.ID,
+		Name: authorEntity.Name,
+	}
+}
+
+func mapToAuthorModels(authorEntities []AuthorEntity) []models.Author {
+	authors := make([]models.Author, 0, len(authorEntities))
+	for i := range authorEntities {
+		authors = append(authors, *mapToAuthorModel(&authorEntities[i]))
+	}
+	return authors
+}
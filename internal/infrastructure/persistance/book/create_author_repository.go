@@ -0,0 +1,30 @@
+package book
+
+import (
+	"context"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+	"gorm.io/gorm"
+)
+
+type CreateAuthorRepository struct {
+	database *gorm.DB
+}
+
+func NewCreateAuthorRepository(database *gorm.DB) *CreateAuthorRepository {
+	return &CreateAuthorRepository{
+		database: database,
+	}
+}
+
+func (c *CreateAuthorRepository) InsertAuthor(ctx context.Context, author *models.Author) (*models.Author, error) {
+	// Map model to entity
+	authorEntity := mapToAuthorEntity(author)
+	// Insert entity
+	result := c.database.WithContext(ctx).Create(authorEntity)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// Map entity to model
+	return mapToAuthorModel(authorEntity), nil
+}
@@ -1,7 +1,9 @@
 package book
 
 import (
+	"context"
 	"errors"
+
 	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
 	"gorm.io/gorm"
 )
@@ -16,28 +18,72 @@ func NewCreateBookRepository(database *gorm.DB) *CreateBookRepository {
 	}
 }
 
-func (c *CreateBookRepository) InsertBook(book *models.Book) (*models.Book, error) {
-	// Map model to entity
-	bookEntity := BookEntity{
-		Isbn:  book.Isbn,
-		Title: book.Title,
+// InsertBook inserts the book, the authors it references by name that do not exist yet and the outbox events of the
+// notifications in a single transaction
+func (c *CreateBookRepository) InsertBook(ctx context.Context, book *models.Book, notifications []models.Notification) (*models.Book, error) {
+	var bookEntity *BookEntity
+	err := c.database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		authors, err := resolveAuthorNames(tx, book.Authors)
+		if err != nil {
+			return err
+		}
+		// Map model to entity
+		resolvedBook := *book
+		resolvedBook.Authors = authors
+		bookEntity = mapToBookEntity(&resolvedBook)
+		outboxEventEntities := make([]*OutboxEventEntity, 0, len(notifications))
+		for _, notification := range notifications {
+			// The notifications carry the IDs of the authors created above
+			notification.Book.Authors = authors
+			outboxEventEntity, err := mapToNotificationOutboxEventEntity(&notification)
+			if err != nil {
+				return err
+			}
+			outboxEventEntities = append(outboxEventEntities, outboxEventEntity)
+		}
+		// Insert entity, the authors exist now so only the join table rows are created
+		if result := tx.Omit("Authors.*").Create(bookEntity); result.Error != nil {
+			return result.Error
+		}
+		if len(outboxEventEntities) == 0 {
+			return nil
+		}
+		return tx.Create(outboxEventEntities).Error
+	})
+	if err != nil {
+		return nil, err
 	}
-	// Insert entity
-	result := c.database.Create(&bookEntity)
+	// Map entity to model
+	return mapToBookModel(bookEntity), nil
+}
+
+func (c *CreateBookRepository) SelectBookByIsbn(ctx context.Context, isbn string) (*models.Book, error) {
+	return selectBookByIsbn(c.database.WithContext(ctx), isbn)
+}
+
+func (c *CreateBookRepository) SelectAuthorsByIds(ctx context.Context, ids []uint) ([]models.Author, error) {
+	return selectAuthorsByIds(c.database.WithContext(ctx), ids)
+}
+
+func (c *CreateBookRepository) SelectNotificationSubscribers(ctx context.Context, event string) ([]models.NotificationRecipient, error) {
+	var notificationSubscriberEntities []NotificationSubscriberEntity
+	result := c.database.WithContext(ctx).Where("event = ? AND active", event).Order("id").Find(&notificationSubscriberEntities)
 	if result.Error != nil {
 		return nil, result.Error
 	}
-	// Map entity to model
-	insert := &models.Book{
-		Isbn:  bookEntity.Isbn,
-		Title: bookEntity.Title,
+	recipients := make([]models.NotificationRecipient, 0, len(notificationSubscriberEntities))
+	for _, notificationSubscriberEntity := range notificationSubscriberEntities {
+		recipients = append(recipients, models.NotificationRecipient{
+			Channel: notificationSubscriberEntity.Channel,
+			Address: notificationSubscriberEntity.Address,
+		})
 	}
-	return insert, nil
+	return recipients, nil
 }
 
-func (c *CreateBookRepository) SelectBookByIsbn(isbn string) (*models.Book, error) {
+func selectBookByIsbn(database *gorm.DB, isbn string) (*models.Book, error) {
 	bookEntity := BookEntity{}
-	result := c.database.Where("isbn = ?", isbn).First(&bookEntity)
+	result := database.Preload("Authors", orderAuthors).Where("isbn = ?", isbn).First(&bookEntity)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -45,9 +91,5 @@ func (c *CreateBookRepository) SelectBookByIsbn(isbn string) (*models.Book, erro
 		return nil, result.Error
 	}
 	// Map entity to model
-	book := &models.Book{
-		Isbn:  bookEntity.Isbn,
-		Title: bookEntity.Title,
-	}
-	return book, nil
+	return mapToBookModel(&bookEntity), nil
 }
@@ -0,0 +1,43 @@
+package book
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type DeleteBookRepository struct {
+	database *gorm.DB
+}
+
+func NewDeleteBookRepository(database *gorm.DB) *DeleteBookRepository {
+	return &DeleteBookRepository{
+		database: database,
+	}
+}
+
+func (c *DeleteBookRepository) DeleteBookByIsbn(ctx context.Context, isbn string) (bool, error) {
+	deleted := false
+	err := c.database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		bookEntity := BookEntity{}
+		result := tx.Where("isbn = ?", isbn).First(&bookEntity)
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if result.Error != nil {
+			return result.Error
+		}
+		// Unlink the authors first, the join table references the book
+		if err := tx.Model(&bookEntity).Association("Authors").Clear(); err != nil {
+			return err
+		}
+		// Hard delete, otherwise the unique isbn index would block creating the book again
+		if result := tx.Unscoped().Delete(&bookEntity); result.Error != nil {
+			return result.Error
+		}
+		deleted = true
+		return nil
+	})
+	return deleted, err
+}
@@ -0,0 +1,68 @@
+package book
+
+import (
+	"context"
+	"time"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+	"gorm.io/gorm"
+)
+
+type DispatchNotificationsRepository struct {
+	database *gorm.DB
+}
+
+func NewDispatchNotificationsRepository(database *gorm.DB) *DispatchNotificationsRepository {
+	return &DispatchNotificationsRepository{
+		database: database,
+	}
+}
+
+// ClaimNotifications sets the lease of the due events in a single statement.
+// SKIP LOCKED lets several dispatchers claim different events concurrently.
+func (c *DispatchNotificationsRepository) ClaimNotifications(ctx context.Context, limit int, lease time.Duration) ([]models.Notification, error) {
+	var outboxEventEntities []OutboxEventEntity
+	result := c.database.WithContext(ctx).Raw(`UPDATE myschema.outbox_events SET locked_until = now() + make_interval(secs => ?), updated_at = now()
+WHERE id IN (
+    SELECT id FROM myschema.outbox_events
+    WHERE event_type = ? AND delivered_at IS NULL AND failed_at IS NULL AND next_attempt_at <= now()
+      AND (locked_until IS NULL OR locked_until < now())
+    ORDER BY next_attempt_at, id
+    LIMIT ?
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *`, lease.Seconds(), EventTypeBookCreatedNotification, limit).Scan(&outboxEventEntities)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	notifications := make([]models.Notification, 0, len(outboxEventEntities))
+	for i := range outboxEventEntities {
+		notification, err := mapToNotificationModel(&outboxEventEntities[i])
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, *notification)
+	}
+	return notifications, nil
+}
+
+func (c *DispatchNotificationsRepository) MarkNotificationDelivered(ctx context.Context, id uint) error {
+	return c.database.WithContext(ctx).Model(&OutboxEventEntity{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"delivered_at": gorm.Expr("now()"),
+		"locked_until": nil,
+	}).Error
+}
+
+func (c *DispatchNotificationsRepository) MarkNotificationFailed(ctx context.Context, id uint, lastError string, nextAttemptAt *time.Time) error {
+	updates := map[string]interface{}{
+		"attempts":     gorm.Expr("attempts + 1"),
+		"last_error":   lastError,
+		"locked_until": nil,
+	}
+	if nextAttemptAt != nil {
+		updates["next_attempt_at"] = *nextAttemptAt
+	} else {
+		updates["failed_at"] = gorm.Expr("now()")
+	}
+	return c.database.WithContext(ctx).Model(&OutboxEventEntity{}).Where("id = ?", id).Updates(updates).Error
+}
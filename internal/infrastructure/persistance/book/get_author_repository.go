@@ -0,0 +1,22 @@
+package book
+
+import (
+	"context"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+	"gorm.io/gorm"
+)
+
+type GetAuthorRepository struct {
+	database *gorm.DB
+}
+
+func NewGetAuthorRepository(database *gorm.DB) *GetAuthorRepository {
+	return &GetAuthorRepository{
+		database: database,
+	}
+}
+
+func (c *GetAuthorRepository) SelectAuthorById(ctx context.Context, id uint) (*models.Author, error) {
+	return selectAuthorById(c.database.WithContext(ctx), id)
+}
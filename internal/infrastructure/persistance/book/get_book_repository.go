@@ -0,0 +1,22 @@
+package book
+
+import (
+	"context"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+	"gorm.io/gorm"
+)
+
+type GetBookRepository struct {
+	database *gorm.DB
+}
+
+func NewGetBookRepository(database *gorm.DB) *GetBookRepository {
+	return &GetBookRepository{
+		database: database,
+	}
+}
+
+func (c *GetBookRepository) SelectBookByIsbn(ctx context.Context, isbn string) (*models.Book, error) {
+	return selectBookByIsbn(c.database.WithContext(ctx), isbn)
+}
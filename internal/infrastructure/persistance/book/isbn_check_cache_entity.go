@@ -0,0 +1,18 @@
+package book
+
+import (
+	"time"
+)
+
+type IsbnCheckCacheEntity struct {
+	Isbn        string    `gorm:"column:isbn;primaryKey"`
+	Valid       bool      `gorm:"column:valid"`
+	CanonicalId string    `gorm:"column:canonical_id"`
+	Title       string    `gorm:"column:title"`
+	ExpiresAt   time.Time `gorm:"column:expires_at"`
+	UpdatedAt   time.Time
+}
+
+func (IsbnCheckCacheEntity) TableName() string {
+	return "myschema.isbn_check_cache"
+}
@@ -0,0 +1,64 @@
+package book
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IsbnCheckCacheRepository is an IsbnCheckCache in Postgres, so it survives restarts and is shared by the instances.
+// It holds up to maxEntries ISBNs, evicting the ones that expire first.
+type IsbnCheckCacheRepository struct {
+	database   *gorm.DB
+	maxEntries int
+}
+
+func NewIsbnCheckCacheRepository(database *gorm.DB, maxEntries int) *IsbnCheckCacheRepository {
+	return &IsbnCheckCacheRepository{
+		database:   database,
+		maxEntries: maxEntries,
+	}
+}
+
+func (c *IsbnCheckCacheRepository) Get(ctx context.Context, isbn string) (*models.IsbnCheck, error) {
+	isbnCheckCacheEntity := IsbnCheckCacheEntity{}
+	result := c.database.WithContext(ctx).Where("isbn = ? AND expires_at > now()", isbn).First(&isbnCheckCacheEntity)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &models.IsbnCheck{
+		Valid:       isbnCheckCacheEntity.Valid,
+		CanonicalId: isbnCheckCacheEntity.CanonicalId,
+		Title:       isbnCheckCacheEntity.Title,
+	}, nil
+}
+
+func (c *IsbnCheckCacheRepository) Set(ctx context.Context, isbn string, isbnCheck *models.IsbnCheck, ttl time.Duration) error {
+	isbnCheckCacheEntity := IsbnCheckCacheEntity{
+		Isbn:        isbn,
+		Valid:       isbnCheck.Valid,
+		CanonicalId: isbnCheck.CanonicalId,
+		Title:       isbnCheck.Title,
+		ExpiresAt:   time.Now().Add(ttl),
+	}
+	return c.database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "isbn"}},
+			DoUpdates: clause.AssignmentColumns([]string{"valid", "canonical_id", "title", "expires_at", "updated_at"}),
+		}).Create(&isbnCheckCacheEntity)
+		if result.Error != nil {
+			return result.Error
+		}
+		// Enforce the size bound, the expired entries go first
+		return tx.Exec(`DELETE FROM myschema.isbn_check_cache WHERE expires_at <= now() OR isbn IN (
+    SELECT isbn FROM myschema.isbn_check_cache ORDER BY expires_at DESC OFFSET ?
+)`, c.maxEntries).Error
+	})
+}
@@ -0,0 +1,34 @@
+package book
+
+import (
+	"context"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+	"gorm.io/gorm"
+)
+
+type ListAuthorBooksRepository struct {
+	database *gorm.DB
+}
+
+func NewListAuthorBooksRepository(database *gorm.DB) *ListAuthorBooksRepository {
+	return &ListAuthorBooksRepository{
+		database: database,
+	}
+}
+
+func (c *ListAuthorBooksRepository) SelectAuthorById(ctx context.Context, id uint) (*models.Author, error) {
+	return selectAuthorById(c.database.WithContext(ctx), id)
+}
+
+func (c *ListAuthorBooksRepository) SelectBooksByAuthorId(ctx context.Context, authorId uint) ([]*models.Book, error) {
+	var bookEntities []BookEntity
+	result := c.database.WithContext(ctx).Preload("Authors", orderAuthors).
+		Where("id IN (SELECT book_id FROM myschema.book_authors WHERE author_id = ?)", authorId).
+		Order("title").Order("id").
+		Find(&bookEntities)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return mapToBookModels(bookEntities), nil
+}
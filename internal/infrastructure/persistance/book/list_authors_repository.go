@@ -0,0 +1,27 @@
+package book
+
+import (
+	"context"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+	"gorm.io/gorm"
+)
+
+type ListAuthorsRepository struct {
+	database *gorm.DB
+}
+
+func NewListAuthorsRepository(database *gorm.DB) *ListAuthorsRepository {
+	return &ListAuthorsRepository{
+		database: database,
+	}
+}
+
+func (c *ListAuthorsRepository) SelectAuthors(ctx context.Context) ([]models.Author, error) {
+	var authorEntities []AuthorEntity
+	result := c.database.WithContext(ctx).Order("name").Order("id").Find(&authorEntities)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return mapToAuthorModels(authorEntities), nil
+}
@@ -0,0 +1,120 @@
+package book
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	servicebook "github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/application/services/books"
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+	"gorm.io/gorm"
+)
+
+type ListBooksRepository struct {
+	database *gorm.DB
+}
+
+func NewListBooksRepository(database *gorm.DB) *ListBooksRepository {
+	return &ListBooksRepository{
+		database: database,
+	}
+}
+
+// bookCursor is the position of the last book of a page. The id breaks the ties of the sort field.
+type bookCursor struct {
+	Value string `json:"value"`
+	ID    uint   `json:"id"`
+}
+
+func (c *ListBooksRepository) SelectBooks(ctx context.Context, query *models.BookListQuery) (*models.BookPage, error) {
+	// Filters
+	db := c.database.WithContext(ctx).Model(&BookEntity{})
+	if query.TitleContains != "" {
+		db = db.Where("title ILIKE ?", "%"+escapeLike(query.TitleContains)+"%")
+	}
+	if query.IsbnPrefix != "" {
+		db = db.Where("isbn LIKE ?", escapeLike(query.IsbnPrefix)+"%")
+	}
+	// New session so the filters can be shared by the count and the page queries
+	db = db.Session(&gorm.Session{})
+	// Total count, before moving to the requested page
+	var totalCount int64
+	if result := db.Count(&totalCount); result.Error != nil {
+		return nil, result.Error
+	}
+	// Keyset pagination
+	sortColumn := string(query.SortBy)
+	direction, comparator := "ASC", ">"
+	if query.Descending {
+		direction, comparator = "DESC", "<"
+	}
+	if query.Cursor != "" {
+		cursor, err := decodeBookCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		cursorValue, err := bookCursorValue(query.SortBy, cursor.Value)
+		if err != nil {
+			return nil, err
+		}
+		db = db.Where(fmt.Sprintf("(%s, id) %s (?, ?)", sortColumn, comparator), cursorValue, cursor.ID)
+	}
+	// One extra row tells if there is a next page
+	var bookEntities []BookEntity
+	result := db.Preload("Authors", orderAuthors).Order(sortColumn + " " + direction).Order("id " + direction).Limit(query.Limit + 1).Find(&bookEntities)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	page := &models.BookPage{
+		TotalCount: totalCount,
+	}
+	if len(bookEntities) > query.Limit {
+		bookEntities = bookEntities[:query.Limit]
+		page.NextCursor = encodeBookCursor(query.SortBy, &bookEntities[len(bookEntities)-1])
+	}
+	// Map entities to models
+	page.Books = mapToBookModels(bookEntities)
+	return page, nil
+}
+
+func encodeBookCursor(sortBy models.BookSortField, bookEntity *BookEntity) string {
+	cursor := bookCursor{ID: bookEntity.ID}
+	if sortBy == models.BookSortByTitle {
+		cursor.Value = bookEntity.Title
+	} else {
+		cursor.Value = bookEntity.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+	cursorJson, _ := json.Marshal(cursor) //nolint:errcheck // a struct of strings always marshals
+	return base64.RawURLEncoding.EncodeToString(cursorJson)
+}
+
+func decodeBookCursor(encoded string) (*bookCursor, error) {
+	cursorJson, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", servicebook.ErrInvalidListQuery)
+	}
+	cursor := &bookCursor{}
+	if err := json.Unmarshal(cursorJson, cursor); err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", servicebook.ErrInvalidListQuery)
+	}
+	return cursor, nil
+}
+
+func bookCursorValue(sortBy models.BookSortField, value string) (interface{}, error) {
+	if sortBy == models.BookSortByTitle {
+		return value, nil
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: cursor does not belong to a listing sorted by %s", servicebook.ErrInvalidListQuery, sortBy)
+	}
+	return createdAt, nil
+}
+
+// escapeLike escapes the LIKE wildcards so the user input is matched literally
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
@@ -0,0 +1,20 @@
+package book
+
+import (
+	"time"
+)
+
+// NotificationSubscriberEntity is a recipient of the notifications of an event, on top of the configured ones
+type NotificationSubscriberEntity struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Event     string `gorm:"column:event"`
+	Channel   string `gorm:"column:channel"`
+	Address   string `gorm:"column:address"`
+	Active    bool   `gorm:"column:active"`
+}
+
+func (NotificationSubscriberEntity) TableName() string {
+	return "myschema.notification_subscribers"
+}
@@ -0,0 +1,55 @@
+package book
+
+import (
+	"time"
+)
+
+// EventTypeBookCreatedNotification is the outbox event of a notification sent to a recipient when a book is created
+const EventTypeBookCreatedNotification = "book_created_notification"
+
+// OutboxEventEntity is an event written in the same transaction as the change that produced it,
+// and delivered afterward by a dispatcher. It is delivered at least once.
+type OutboxEventEntity struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	EventType string `gorm:"column:event_type"`
+	// Payload is the JSON of the event, kept as a string so the simple protocol does not encode it as bytea
+	Payload       string     `gorm:"column:payload;type:jsonb"`
+	Attempts      int        `gorm:"column:attempts"`
+	NextAttemptAt time.Time  `gorm:"column:next_attempt_at"`
+	LockedUntil   *time.Time `gorm:"column:locked_until"`
+	LastError     string     `gorm:"column:last_error"`
+	DeliveredAt   *time.Time `gorm:"column:delivered_at"`
+	// FailedAt is set when there are no retries left
+	FailedAt *time.Time `gorm:"column:failed_at"`
+}
+
+func (OutboxEventEntity) TableName() string {
+	return "myschema.outbox_events"
+}
+
+// notificationPayload is the payload of the EventTypeBookCreatedNotification events.
+// The book is copied so the notification describes it as it was when it was created.
+type notificationPayload struct {
+	Event   string                  `json:"event"`
+	Channel string                  `json:"channel"`
+	Address string                  `json:"address"`
+	Book    notificationBookPayload `json:"book"`
+}
+
+type notificationBookPayload struct {
+	Isbn            string                      `json:"isbn"`
+	Title           string                      `json:"title"`
+	Authors         []notificationAuthorPayload `json:"authors,omitempty"`
+	TotalPages      int                         `json:"total_pages,omitempty"`
+	Publisher       string                      `json:"publisher,omitempty"`
+	PublicationDate *time.Time                  `json:"publication_date,omitempty"`
+	Language        string                      `json:"language,omitempty"`
+	Description     string                      `json:"description,omitempty"`
+}
+
+type notificationAuthorPayload struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
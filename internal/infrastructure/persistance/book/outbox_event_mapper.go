@@ -0,0 +1,70 @@
+package book
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+)
+
+func mapToNotificationOutboxEventEntity(notification *models.Notification) (*OutboxEventEntity, error) {
+	book := notification.Book
+	authors := make([]notificationAuthorPayload, 0, len(book.Authors))
+	for _, author := range book.Authors {
+		authors = append(authors, notificationAuthorPayload{ID: author.ID, Name: author.Name})
+	}
+	payload, err := json.Marshal(notificationPayload{
+		Event:   notification.Event,
+		Channel: notification.Recipient.Channel,
+		Address: notification.Recipient.Address,
+		Book: notificationBookPayload{
+			Isbn:            book.Isbn,
+			Title:           book.Title,
+			Authors:         authors,
+			TotalPages:      book.TotalPages,
+			Publisher:       book.Publisher,
+			PublicationDate: book.PublicationDate,
+			Language:        book.Language,
+			Description:     book.Description,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling the notification payload: %w", err)
+	}
+	return &OutboxEventEntity{
+		EventType:     EventTypeBookCreatedNotification,
+		Payload:       string(payload),
+		NextAttemptAt: time.Now(),
+	}, nil
+}
+
+func mapToNotificationModel(outboxEventEntity *OutboxEventEntity) (*models.Notification, error) {
+	payload := notificationPayload{}
+	if err := json.Unmarshal([]byte(outboxEventEntity.Payload), &payload); err != nil {
+		return nil, fmt.Errorf("error unmarshalling the payload of outbox event %d: %w", outboxEventEntity.ID, err)
+	}
+	authors := make([]models.Author, 0, len(payload.Book.Authors))
+	for _, author := range payload.Book.Authors {
+		authors = append(authors, models.Author{ID: author.ID, Name: author.Name})
+	}
+	return &models.Notification{
+		ID:    outboxEventEntity.ID,
+		Event: payload.Event,
+		Recipient: models.NotificationRecipient{
+			Channel: payload.Channel,
+			Address: payload.Address,
+		},
+		Book: models.Book{
+			Isbn:            payload.Book.Isbn,
+			Title:           payload.Book.Title,
+			Authors:         authors,
+			TotalPages:      payload.Book.TotalPages,
+			Publisher:       payload.Book.Publisher,
+			PublicationDate: payload.Book.PublicationDate,
+			Language:        payload.Book.Language,
+			Description:     payload.Book.Description,
+		},
+		Attempts: outboxEventEntity.Attempts,
+	}, nil
+}
@@ -0,0 +1,61 @@
+package book
+
+import (
+	"context"
+
+	"github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/domain/models"
+	"gorm.io/gorm"
+)
+
+type UpdateBookRepository struct {
+	database *gorm.DB
+}
+
+func NewUpdateBookRepository(database *gorm.DB) *UpdateBookRepository {
+	return &UpdateBookRepository{
+		database: database,
+	}
+}
+
+func (c *UpdateBookRepository) SelectBookByIsbn(ctx context.Context, isbn string) (*models.Book, error) {
+	return selectBookByIsbn(c.database.WithContext(ctx), isbn)
+}
+
+func (c *UpdateBookRepository) SelectAuthorsByIds(ctx context.Context, ids []uint) ([]models.Author, error) {
+	return selectAuthorsByIds(c.database.WithContext(ctx), ids)
+}
+
+// UpdateBook updates the book and creates the authors it references by name that do not exist yet in a single
+// transaction
+func (c *UpdateBookRepository) UpdateBook(ctx context.Context, book *models.Book) (*models.Book, error) {
+	err := c.database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		storedBookEntity := BookEntity{}
+		if result := tx.Where("isbn = ?", book.Isbn).First(&storedBookEntity); result.Error != nil {
+			return result.Error
+		}
+		authors, err := resolveAuthorNames(tx, book.Authors)
+		if err != nil {
+			return err
+		}
+		resolvedBook := *book
+		resolvedBook.Authors = authors
+		bookEntity := mapToBookEntity(&resolvedBook)
+		// A map is used so zero values are written as well
+		result := tx.Model(&storedBookEntity).Updates(map[string]interface{}{
+			"title":            bookEntity.Title,
+			"total_pages":      bookEntity.TotalPages,
+			"publisher":        bookEntity.Publisher,
+			"publication_date": bookEntity.PublicationDate,
+			"language":         bookEntity.Language,
+			"description":      bookEntity.Description,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		return tx.Model(&storedBookEntity).Association("Authors").Replace(bookEntity.Authors)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return selectBookByIsbn(c.database.WithContext(ctx), book.Isbn)
+}
@@ -0,0 +1,96 @@
+// Package migrations applies the versioned SQL migrations of the database schema.
+//
+// Each migration is a pair of files in the sql directory, NNNN_name.up.sql and NNNN_name.down.sql.
+// The applied migrations are recorded in public.schema_migrations with the checksum of their up file,
+// so a migration edited after being applied is detected instead of silently diverging.
+package migrations
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+//go:embed sql/*.sql
+var embeddedMigrations embed.FS
+
+// Migrations returns the migrations of the application, sorted by version
+func Migrations() ([]Migration, error) {
+	source, err := fs.Sub(embeddedMigrations, "sql")
+	if err != nil {
+		return nil, err
+	}
+	return LoadMigrations(source)
+}
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+	// Checksum is the SHA-256 of Up
+	Checksum string
+}
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// LoadMigrations reads the migrations in the root of source, sorted by version.
+// Every version must have both the up and the down file.
+func LoadMigrations(source fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(source, ".")
+	if err != nil {
+		return nil, fmt.Errorf("error reading migrations: %w", err)
+	}
+	migrationsByVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		match := migrationFileName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			return nil, fmt.Errorf("unexpected migration file %s, it must be named NNNN_name.up.sql or NNNN_name.down.sql", entry.Name())
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing the version of migration file %s: %w", entry.Name(), err)
+		}
+		content, err := fs.ReadFile(source, path.Join(".", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("error reading migration file %s: %w", entry.Name(), err)
+		}
+		migration, ok := migrationsByVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			migrationsByVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = string(content)
+			checksum := sha256.Sum256(content)
+			migration.Checksum = hex.EncodeToString(checksum[:])
+		} else {
+			migration.Down = string(content)
+		}
+	}
+	migrations := make([]Migration, 0, len(migrationsByVersion))
+	for _, migration := range migrationsByVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s must have both an up and a down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// String returns the name of the files of the migration without the direction, e.g. 0001_create_books
+func (m Migration) String() string {
+	return fmt.Sprintf("%04d_%s", m.Version, m.Name)
+}
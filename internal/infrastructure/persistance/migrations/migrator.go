@@ -0,0 +1,212 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// ErrChecksumMismatch is returned when an applied migration was edited afterward
+var ErrChecksumMismatch = errors.New("migration checksum mismatch")
+
+// ErrPendingMigrations is returned by Verify when the database is behind the migrations
+var ErrPendingMigrations = errors.New("pending migrations")
+
+// lockId identifies the advisory lock taken while migrating, so concurrent migrators wait for each other
+const lockId = 7_206_483_112
+
+const createMigrationsTable = `CREATE TABLE IF NOT EXISTS public.schema_migrations
+(
+    version    bigint PRIMARY KEY,
+    name       text        NOT NULL,
+    checksum   text        NOT NULL,
+    applied_at timestamptz NOT NULL DEFAULT now()
+)`
+
+// MigrationStatus is a migration and when it was applied, AppliedAt is nil when pending
+type MigrationStatus struct {
+	Migration Migration
+	AppliedAt *time.Time
+}
+
+type appliedMigration struct {
+	checksum  string
+	appliedAt time.Time
+}
+
+type Migrator struct {
+	database   *sql.DB
+	migrations []Migration
+	logger     *slog.Logger
+}
+
+func NewMigrator(database *sql.DB, migrations []Migration, logger *slog.Logger) *Migrator {
+	return &Migrator{
+		database:   database,
+		migrations: migrations,
+		logger:     logger,
+	}
+}
+
+// Up applies the pending migrations in order, each one in its own transaction.
+// It fails without applying anything when an applied migration has a different checksum.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verifyChecksums(applied); err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			err := inTransaction(ctx, conn, func(tx *sql.Tx) error {
+				if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+					return err
+				}
+				_, err := tx.ExecContext(ctx, `INSERT INTO public.schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`,
+					migration.Version, migration.Name, migration.Checksum)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("error applying migration %s: %w", migration, err)
+			}
+			m.logger.InfoContext(ctx, "Applied migration", slog.String("migration", migration.String()))
+		}
+		return nil
+	})
+}
+
+// Down reverts the last steps applied migrations, newest first
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verifyChecksums(applied); err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && steps > 0; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			err := inTransaction(ctx, conn, func(tx *sql.Tx) error {
+				if _, err := tx.ExecContext(ctx, migration.Down); err != nil {
+					return err
+				}
+				_, err := tx.ExecContext(ctx, `DELETE FROM public.schema_migrations WHERE version = $1`, migration.Version)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("error reverting migration %s: %w", migration, err)
+			}
+			m.logger.InfoContext(ctx, "Reverted migration", slog.String("migration", migration.String()))
+			steps--
+		}
+		return nil
+	})
+}
+
+// Status lists every migration and whether it is applied
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			status := MigrationStatus{Migration: migration}
+			if appliedMigration, ok := applied[migration.Version]; ok {
+				status.AppliedAt = &appliedMigration.appliedAt
+			}
+			statuses = append(statuses, status)
+		}
+		return nil
+	})
+	return statuses, err
+}
+
+// Verify fails when there are pending migrations or an applied migration has a different checksum
+func (m *Migrator) Verify(ctx context.Context) error {
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verifyChecksums(applied); err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; !ok {
+				return fmt.Errorf("%w: %s is not applied, run the migrate up command", ErrPendingMigrations, migration)
+			}
+		}
+		return nil
+	})
+}
+
+func (m *Migrator) verifyChecksums(applied map[int64]appliedMigration) error {
+	for _, migration := range m.migrations {
+		if appliedMigration, ok := applied[migration.Version]; ok && appliedMigration.checksum != migration.Checksum {
+			return fmt.Errorf("%w: %s was applied with checksum %s but the file has %s",
+				ErrChecksumMismatch, migration, appliedMigration.checksum, migration.Checksum)
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) applied(ctx context.Context, conn *sql.Conn) (map[int64]appliedMigration, error) {
+	if _, err := conn.ExecContext(ctx, createMigrationsTable); err != nil {
+		return nil, fmt.Errorf("error creating the migrations table: %w", err)
+	}
+	rows, err := conn.QueryContext(ctx, `SELECT version, checksum, applied_at FROM public.schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the applied migrations: %w", err)
+	}
+	defer rows.Close()
+	applied := map[int64]appliedMigration{}
+	for rows.Next() {
+		var version int64
+		var migration appliedMigration
+		if err := rows.Scan(&version, &migration.checksum, &migration.appliedAt); err != nil {
+			return nil, fmt.Errorf("error reading the applied migrations: %w", err)
+		}
+		applied[version] = migration
+	}
+	return applied, rows.Err()
+}
+
+// withLock runs fn on a single connection holding the migrations advisory lock
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.database.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("error connecting to the database: %w", err)
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, lockId); err != nil {
+		return fmt.Errorf("error taking the migrations lock: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, lockId) //nolint:errcheck // released with the session anyway
+	return fn(conn)
+}
+
+func inTransaction(ctx context.Context, conn *sql.Conn, fn func(tx *sql.Tx) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback() //nolint:errcheck // the error of fn is more relevant
+		return err
+	}
+	return tx.Commit()
+}
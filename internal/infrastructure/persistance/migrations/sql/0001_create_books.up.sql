@@ -0,0 +1,15 @@
+-- Databases created before the migrations were introduced already have the table, created by gorm AutoMigrate
+CREATE SCHEMA IF NOT EXISTS myschema;
+
+CREATE TABLE IF NOT EXISTS myschema.books
+(
+    id         bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    isbn       text,
+    title      text,
+    CONSTRAINT uni_books_isbn UNIQUE (isbn)
+);
+
+CREATE INDEX IF NOT EXISTS idx_books_deleted_at ON myschema.books (deleted_at);
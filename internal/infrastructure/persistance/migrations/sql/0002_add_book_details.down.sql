@@ -0,0 +1,6 @@
+ALTER TABLE myschema.books
+    DROP COLUMN IF EXISTS total_pages,
+    DROP COLUMN IF EXISTS publisher,
+    DROP COLUMN IF EXISTS publication_date,
+    DROP COLUMN IF EXISTS language,
+    DROP COLUMN IF EXISTS description;
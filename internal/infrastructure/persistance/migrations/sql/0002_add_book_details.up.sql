@@ -0,0 +1,6 @@
+ALTER TABLE myschema.books
+    ADD COLUMN IF NOT EXISTS total_pages      bigint,
+    ADD COLUMN IF NOT EXISTS publisher        text,
+    ADD COLUMN IF NOT EXISTS publication_date date,
+    ADD COLUMN IF NOT EXISTS language         text,
+    ADD COLUMN IF NOT EXISTS description      text;
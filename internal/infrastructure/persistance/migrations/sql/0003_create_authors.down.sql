@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS myschema.book_authors;
+
+DROP TABLE IF EXISTS myschema.authors;
@@ -0,0 +1,59 @@
+CREATE TABLE IF NOT EXISTS myschema.authors
+(
+    id         bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    name       text
+);
+
+CREATE INDEX IF NOT EXISTS idx_authors_deleted_at ON myschema.authors (deleted_at);
+
+CREATE TABLE IF NOT EXISTS myschema.book_authors
+(
+    book_id   bigint NOT NULL REFERENCES myschema.books (id),
+    author_id bigint NOT NULL REFERENCES myschema.authors (id),
+    PRIMARY KEY (book_id, author_id)
+);
+
+-- The authors were stored as a text array before being a resource, in the column created by gorm AutoMigrate. Their names
+-- are copied to the new tables before the column is dropped: the missing authors are inserted in the order they first
+-- appear, so the authors of a book keep the order of its array, and every name is linked to its first author
+DO
+$$
+    BEGIN
+        IF EXISTS (SELECT 1
+                   FROM information_schema.columns
+                   WHERE table_schema = 'myschema'
+                     AND table_name = 'books'
+                     AND column_name = 'authors') THEN
+            INSERT INTO myschema.authors (created_at, updated_at, name)
+            SELECT now(), now(), first_appearance.name
+            FROM (SELECT DISTINCT ON (author.name) author.name, book.id AS book_id, author.number
+                  FROM myschema.books book
+                           CROSS JOIN LATERAL unnest(book.authors) WITH ORDINALITY AS author(name, number)
+                  WHERE btrim(author.name) <> ''
+                  ORDER BY author.name, book.id, author.number) first_appearance
+            WHERE NOT EXISTS (SELECT 1
+                              FROM myschema.authors existing
+                              WHERE existing.name = first_appearance.name
+                                AND existing.deleted_at IS NULL)
+            ORDER BY first_appearance.book_id, first_appearance.number;
+
+            INSERT INTO myschema.book_authors (book_id, author_id)
+            SELECT book.id,
+                   (SELECT min(existing.id)
+                    FROM myschema.authors existing
+                    WHERE existing.name = author.name
+                      AND existing.deleted_at IS NULL)
+            FROM myschema.books book
+                     CROSS JOIN LATERAL unnest(book.authors) WITH ORDINALITY AS author(name, number)
+            WHERE btrim(author.name) <> ''
+            ORDER BY book.id, author.number
+            ON CONFLICT DO NOTHING;
+        END IF;
+    END
+$$;
+
+ALTER TABLE myschema.books
+    DROP COLUMN IF EXISTS authors;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS myschema.outbox_events;
@@ -0,0 +1,18 @@
+CREATE TABLE myschema.outbox_events
+(
+    id              bigserial PRIMARY KEY,
+    created_at      timestamptz NOT NULL DEFAULT now(),
+    updated_at      timestamptz NOT NULL DEFAULT now(),
+    event_type      text        NOT NULL,
+    payload         jsonb       NOT NULL,
+    attempts        integer     NOT NULL DEFAULT 0,
+    next_attempt_at timestamptz NOT NULL DEFAULT now(),
+    locked_until    timestamptz,
+    last_error      text        NOT NULL DEFAULT '',
+    delivered_at    timestamptz,
+    failed_at       timestamptz
+);
+
+-- The dispatcher only looks for the events that are neither delivered nor failed
+CREATE INDEX idx_outbox_events_pending ON myschema.outbox_events (next_attempt_at)
+    WHERE delivered_at IS NULL AND failed_at IS NULL;
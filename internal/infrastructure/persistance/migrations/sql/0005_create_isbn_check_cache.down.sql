@@ -0,0 +1 @@
+DROP TABLE IF EXISTS myschema.isbn_check_cache;
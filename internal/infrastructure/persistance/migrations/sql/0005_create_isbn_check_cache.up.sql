@@ -0,0 +1,12 @@
+CREATE TABLE myschema.isbn_check_cache
+(
+    isbn         text PRIMARY KEY,
+    valid        boolean     NOT NULL,
+    canonical_id text        NOT NULL DEFAULT '',
+    title        text        NOT NULL DEFAULT '',
+    expires_at   timestamptz NOT NULL,
+    updated_at   timestamptz NOT NULL DEFAULT now()
+);
+
+-- The size bound evicts the entries that expire first
+CREATE INDEX idx_isbn_check_cache_expires_at ON myschema.isbn_check_cache (expires_at);
@@ -0,0 +1,10 @@
+UPDATE myschema.outbox_events
+SET event_type = 'book_email_notification',
+    payload    = jsonb_build_object('email', payload -> 'address', 'book', payload -> 'book')
+WHERE event_type = 'book_created_notification'
+  AND payload ->> 'channel' = 'email_api';
+
+-- The notifications of the other channels cannot be represented anymore
+DELETE FROM myschema.outbox_events WHERE event_type = 'book_created_notification';
+
+DROP TABLE IF EXISTS myschema.notification_subscribers;
@@ -0,0 +1,18 @@
+CREATE TABLE myschema.notification_subscribers
+(
+    id         bigserial PRIMARY KEY,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    event      text        NOT NULL,
+    channel    text        NOT NULL,
+    address    text        NOT NULL,
+    active     boolean     NOT NULL DEFAULT true,
+    CONSTRAINT uni_notification_subscribers UNIQUE (event, channel, address)
+);
+
+-- The pending emails become notifications of the email API channel
+UPDATE myschema.outbox_events
+SET event_type = 'book_created_notification',
+    payload    = jsonb_build_object('event', 'book_created', 'channel', 'email_api',
+                                    'address', payload -> 'email', 'book', payload -> 'book')
+WHERE event_type = 'book_email_notification';
@@ -0,0 +1,63 @@
+// Package requestid carries the ID of a request through its context, from the incoming request to the outbound ones.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// Header carries the ID of a request, generated when the client does not send one
+const Header = "X-Request-ID"
+
+type contextKey struct{}
+
+// New returns a random ID
+func New() string {
+	id := make([]byte, 16)
+	rand.Read(id) //nolint:errcheck // crypto/rand never fails
+	return hex.EncodeToString(id)
+}
+
+// NewContext returns a copy of ctx with the request ID
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID of ctx, empty when it has none
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// Transport forwards the request ID of the context to the outbound requests
+type Transport struct {
+	base http.RoundTripper
+}
+
+// NewTransport wraps base, http.DefaultTransport when nil
+func NewTransport(base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{base: base}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	id := FromContext(req.Context())
+	if id == "" || req.Header.Get(Header) != "" {
+		return t.base.RoundTrip(req)
+	}
+	// A RoundTripper must not modify the request
+	req = req.Clone(req.Context())
+	req.Header.Set(Header, id)
+	return t.base.RoundTrip(req)
+}
+
+// CloseIdleConnections closes the idle connections of the base transport, so http.Client.CloseIdleConnections reaches it
+func (t *Transport) CloseIdleConnections() {
+	if closer, ok := t.base.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
@@ -0,0 +1,74 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	servicebook "github.com/joseboretto/golang-testcontainers-gherkin-setup/internal/application/services/books"
+)
+
+// NotificationWorker dispatches the notifications of the outbox in the background
+type NotificationWorker struct {
+	dispatchNotificationsServiceInterface servicebook.DispatchNotificationsServiceInterface
+	pollInterval                          time.Duration
+	batchSize                             int
+	logger                                *slog.Logger
+	cancel                                context.CancelFunc
+	done                                  sync.WaitGroup
+}
+
+func NewNotificationWorker(dispatchNotificationsServiceInterface servicebook.DispatchNotificationsServiceInterface,
+	pollInterval time.Duration, batchSize int, logger *slog.Logger) *NotificationWorker {
+	return &NotificationWorker{
+		dispatchNotificationsServiceInterface: dispatchNotificationsServiceInterface,
+		pollInterval:                          pollInterval,
+		batchSize:                             batchSize,
+		logger:                                logger,
+	}
+}
+
+// Start polls the outbox every pollInterval until ctx is done or Stop is called
+func (w *NotificationWorker) Start(ctx context.Context) {
+	ctx, w.cancel = context.WithCancel(ctx)
+	w.done.Add(1)
+	go func() {
+		defer w.done.Done()
+		ticker := time.NewTicker(w.pollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				w.dispatch(ctx)
+			}
+		}
+	}()
+}
+
+// Stop cancels the batch in progress and waits for the worker to exit.
+// The notification being sent by the cancelled batch is retried once its lease expires.
+func (w *NotificationWorker) Stop() {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	w.done.Wait()
+}
+
+// dispatch drains the due notifications, batch by batch
+func (w *NotificationWorker) dispatch(ctx context.Context) {
+	for ctx.Err() == nil {
+		dispatched, err := w.dispatchNotificationsServiceInterface.DispatchNotifications(ctx, w.batchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				w.logger.ErrorContext(ctx, "Error dispatching notifications", slog.Any("error", err))
+			}
+			return
+		}
+		if dispatched < w.batchSize {
+			return
+		}
+	}
+}
@@ -1 +0,0 @@
-create schema myschema;
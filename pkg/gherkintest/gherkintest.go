@@ -0,0 +1,148 @@
+// Package gherkintest runs godog features against an app backed by a postgres container and mocked third-party APIs.
+// Every scenario gets its own app, database and mock transport, so the scenarios run concurrently.
+//
+//	suite, err := gherkintest.New(t).
+//		WithPostgres("docker.io/postgres:16-alpine", "db", migrate).
+//		WithTruncatedSchemas("myschema").
+//		WithMockHost("check_isbn", "https://api.isbncheck.com").
+//		WithApp(newApp).
+//		WithFeaturePaths("features").
+//		Suite(ctx)
+package gherkintest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/cucumber/godog"
+)
+
+// Builder configures the suite of the features, see New
+type Builder struct {
+	t           testing.TB
+	params      TestContainersParams
+	app         AppOptions
+	paths       []string
+	format      string
+	concurrency int
+	steps       []func(sc *godog.ScenarioContext, s *StepsContext)
+}
+
+// New returns a builder with a postgres 16 database named "db", the features in "features" and one scenario per CPU
+// at a time. With a *testing.T the scenarios run as its subtests.
+func New(t testing.TB) *Builder {
+	return &Builder{
+		t: t,
+		params: TestContainersParams{
+			PostgresImage: "docker.io/postgres:16-alpine",
+			DatabaseName:  "db",
+		},
+		app: AppOptions{
+			MockHosts: map[string]string{},
+		},
+		paths:       []string{"features"},
+		format:      "pretty",
+		concurrency: runtime.GOMAXPROCS(0),
+	}
+}
+
+// WithApp sets the factory of the app of every scenario
+func (b *Builder) WithApp(factory AppFactory) *Builder {
+	b.app.Factory = factory
+	return b
+}
+
+// WithPostgres sets the image of the postgres container and the database migrated once with migrate, then copied for
+// every scenario
+func (b *Builder) WithPostgres(image, databaseName string, migrate func(ctx context.Context, db *sql.DB) error) *Builder {
+	b.params.PostgresImage = image
+	b.params.DatabaseName = databaseName
+	b.params.Migrate = migrate
+	return b
+}
+
+// WithTruncatedSchemas sets the schemas whose tables are emptied before the app of every scenario is built
+func (b *Builder) WithTruncatedSchemas(schemas ...string) *Builder {
+	b.params.TruncatedSchemas = schemas
+	return b
+}
+
+// WithMockHost adds a mocked service, passed to the app factory in AppEnv.MockHosts
+func (b *Builder) WithMockHost(name, url string) *Builder {
+	b.app.MockHosts[name] = url
+	return b
+}
+
+// WithSteps adds steps of the app, registered for every scenario after the steps of the harness
+func (b *Builder) WithSteps(register func(sc *godog.ScenarioContext, s *StepsContext)) *Builder {
+	b.steps = append(b.steps, register)
+	return b
+}
+
+// WithFeaturePaths sets the feature files, or the directories of the feature files, to run
+func (b *Builder) WithFeaturePaths(paths ...string) *Builder {
+	b.paths = paths
+	return b
+}
+
+// WithFormat sets the godog formatter of the results, "pretty" by default
+func (b *Builder) WithFormat(format string) *Builder {
+	b.format = format
+	return b
+}
+
+// WithConcurrency sets the number of scenarios run at the same time
+func (b *Builder) WithConcurrency(concurrency int) *Builder {
+	b.concurrency = concurrency
+	return b
+}
+
+// WithHttptestServer serves the apps with an httptest.Server instead of their own http.Server
+func (b *Builder) WithHttptestServer(enabled bool) *Builder {
+	b.app.UseHttptestServer = enabled
+	return b
+}
+
+// Suite starts the postgres container and returns the suite of the features. The container is terminated with
+// t.Cleanup, also when an error is returned.
+func (b *Builder) Suite(ctx context.Context) (godog.TestSuite, error) {
+	if b.app.Factory == nil {
+		return godog.TestSuite{}, errors.New("the app factory is required, see WithApp")
+	}
+	if b.concurrency < 1 {
+		return godog.TestSuite{}, errors.New("the concurrency must be a positive number")
+	}
+	containers, err := NewTestContainersContext(ctx, b.params)
+	b.t.Cleanup(func() {
+		if err := containers.Close(context.Background()); err != nil {
+			b.t.Errorf("failed to clean up the test containers: %v", err)
+		}
+	})
+	if err != nil {
+		return godog.TestSuite{}, err
+	}
+	app := b.app
+	steps := b.steps
+	// The scenarios only run as subtests of a *testing.T
+	testingT, _ := b.t.(*testing.T)
+	return godog.TestSuite{
+		ScenarioInitializer: func(sc *godog.ScenarioContext) {
+			// Every scenario runs against its own app, database and mock transport
+			s := NewStepsContext(func(ctx context.Context, tags []string) (*ScenarioApp, error) {
+				return containers.NewScenarioApp(ctx, app, tags)
+			}, sc)
+			for _, register := range steps {
+				register(sc, s)
+			}
+		},
+		Options: &godog.Options{
+			Format:      b.format,
+			Paths:       b.paths,
+			Concurrency: b.concurrency,
+			TestingT:    testingT, // Testing instance that will run subtests.
+		},
+	}, nil
+}
@@ -0,0 +1,207 @@
+package gherkintest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/lib/pq"
+)
+
+// AppEnv is what the app of a scenario is built with
+type AppEnv struct {
+	// Name identifies the scenario, e.g. scenario_3
+	Name string
+	// Database is the database of the scenario, copied from the migrated template
+	Database Database
+	// HttpClient sends the requests of the app to the mock transport of the scenario
+	HttpClient *http.Client
+	// MockHosts are the base URLs of the mocked services by name, the app calls them through HttpClient
+	MockHosts map[string]string
+	// Tags are the tags of the scenario, e.g. @isbn_cache_memory, so the factory can configure the app per scenario
+	Tags []string
+}
+
+// AppFactory builds the app of a scenario. The harness serves it on an ephemeral port, so the address of the server is
+// ignored, and calls the returned function once the server is stopped.
+type AppFactory func(ctx context.Context, env AppEnv) (*http.Server, func(), error)
+
+// AppOptions is how the apps of the scenarios are built and served, the same for every scenario
+type AppOptions struct {
+	// Factory builds the app of a scenario
+	Factory AppFactory
+	// MockHosts are the base URLs of the mocked services by name, see AppEnv.MockHosts
+	MockHosts map[string]string
+	// UseHttptestServer serves the handler of the app with an httptest.Server instead of its own http.Server
+	UseHttptestServer bool
+}
+
+// ScenarioApp is an instance of the app for a single scenario, so the scenarios can run concurrently
+type ScenarioApp struct {
+	// URL is the address the app listens on, e.g. http://127.0.0.1:41234
+	URL string
+	// Database is a connection to the database of the scenario
+	Database *sql.DB
+	// MockTransport answers the requests of the app to the third-party APIs
+	MockTransport *httpmock.MockTransport
+	close         func(ctx context.Context) error
+}
+
+// NewScenarioApp starts an app on a database no other running scenario uses, listening on an ephemeral port.
+// The tables of the truncated schemas are emptied before the app is built. The tags of the scenario are passed to the
+// app factory.
+func (c *TestContainersContext) NewScenarioApp(ctx context.Context, options AppOptions, tags []string) (*ScenarioApp, error) {
+	name := fmt.Sprintf("scenario_%d", c.scenarios.Add(1))
+	databaseName, err := c.acquireDatabase(ctx)
+	if err != nil {
+		return nil, err
+	}
+	database := c.template
+	database.Name = databaseName
+	db, err := sql.Open("postgres", database.DSN())
+	if err != nil {
+		c.releaseDatabase(databaseName)
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	// The tables are emptied before the app starts, so its background workers never see the rows of the previous
+	// scenario on the database
+	if err := truncateTables(ctx, db, c.Params.TruncatedSchemas); err != nil {
+		c.releaseDatabase(databaseName)
+		return nil, errors.Join(err, db.Close())
+	}
+	// Mock the third-party API client
+	mockTransport := httpmock.NewMockTransport()
+	// Build the app
+	appCtx, cancelApp := context.WithCancel(context.Background())
+	server, deferFn, err := options.Factory(appCtx, AppEnv{
+		Name:       name,
+		Database:   database,
+		HttpClient: &http.Client{Transport: mockTransport},
+		MockHosts:  options.MockHosts,
+		Tags:       tags,
+	})
+	if err != nil {
+		cancelApp()
+		c.releaseDatabase(databaseName)
+		return nil, errors.Join(fmt.Errorf("failed to set up the app: %w", err), db.Close())
+	}
+	url, stop, err := serve(server, name, options.UseHttptestServer)
+	if err != nil {
+		cancelApp()
+		deferFn()
+		c.releaseDatabase(databaseName)
+		return nil, errors.Join(err, db.Close())
+	}
+	return &ScenarioApp{
+		URL:           url,
+		Database:      db,
+		MockTransport: mockTransport,
+		close: func(ctx context.Context) error {
+			stopErr := stop(ctx)
+			cancelApp()
+			deferFn()
+			mockTransport.Reset()
+			closeErr := db.Close()
+			c.releaseDatabase(databaseName)
+			return errors.Join(stopErr, closeErr)
+		},
+	}, nil
+}
+
+// serve starts the app on an ephemeral port and returns its URL.
+// With useHttptestServer the handler is served by an httptest.Server instead of the http.Server of the app.
+func serve(server *http.Server, name string, useHttptestServer bool) (string, func(ctx context.Context) error, error) {
+	if useHttptestServer {
+		testServer := httptest.NewUnstartedServer(server.Handler)
+		// Keep the timeouts and the base context of the app
+		testServer.Config = server
+		testServer.Start()
+		return testServer.URL, func(context.Context) error {
+			testServer.Close()
+			return nil
+		}, nil
+	}
+	// The port is bound before the server starts, so the app is ready once Listen returns: the kernel queues the
+	// connections of the first steps until Serve accepts them, no need to wait for it
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to listen: %w", err)
+	}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("Error serving the scenario %s: %v", name, err)
+		}
+	}()
+	return "http://" + listener.Addr().String(), server.Shutdown, nil
+}
+
+// Close stops the app and gives its database back to the next scenarios
+func (a *ScenarioApp) Close(ctx context.Context) error {
+	return a.close(ctx)
+}
+
+// acquireDatabase returns a database no running scenario uses, copied from the migrated template when none is idle.
+// There are at most as many databases as scenarios running at the same time.
+func (c *TestContainersContext) acquireDatabase(ctx context.Context) (string, error) {
+	c.databasesLock.Lock()
+	defer c.databasesLock.Unlock()
+	if n := len(c.idleDatabases); n > 0 {
+		name := c.idleDatabases[n-1]
+		c.idleDatabases = c.idleDatabases[:n-1]
+		return name, nil
+	}
+	c.databases++
+	name := fmt.Sprintf("%s_%d", c.Params.DatabaseName, c.databases)
+	// Postgres rejects concurrent copies of the same template, the lock serializes them
+	statement := fmt.Sprintf("CREATE DATABASE %s TEMPLATE %s", pq.QuoteIdentifier(name), pq.QuoteIdentifier(c.Params.DatabaseName))
+	if _, err := c.Database.ExecContext(ctx, statement); err != nil {
+		return "", fmt.Errorf("error creating the database %s: %w", name, err)
+	}
+	return name, nil
+}
+
+// releaseDatabase makes the database available to the next scenarios, which truncate its tables before their app starts
+func (c *TestContainersContext) releaseDatabase(name string) {
+	c.databasesLock.Lock()
+	defer c.databasesLock.Unlock()
+	c.idleDatabases = append(c.idleDatabases, name)
+}
+
+// truncateTables empties every table of the truncated schemas and restarts their identities, so the generated ids
+// are the same in every run. The tables of the other schemas, such as the migrations table, are kept.
+func truncateTables(ctx context.Context, db *sql.DB, schemas []string) error {
+	if len(schemas) == 0 {
+		return nil
+	}
+	rows, err := db.QueryContext(ctx, `SELECT quote_ident(table_schema) || '.' || quote_ident(table_name)
+		FROM information_schema.tables WHERE table_schema = ANY($1) AND table_type = 'BASE TABLE'`, pq.Array(schemas))
+	if err != nil {
+		return fmt.Errorf("error listing the tables: %w", err)
+	}
+	defer rows.Close()
+	var tables []string
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			return fmt.Errorf("error listing the tables: %w", err)
+		}
+		tables = append(tables, table)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error listing the tables: %w", err)
+	}
+	if len(tables) == 0 {
+		return nil
+	}
+	if _, err := db.ExecContext(ctx, "TRUNCATE TABLE "+strings.Join(tables, ", ")+" RESTART IDENTITY CASCADE"); err != nil {
+		return fmt.Errorf("error truncating the tables: %w", err)
+	}
+	return nil
+}
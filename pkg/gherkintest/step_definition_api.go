@@ -1,20 +1,30 @@
-package main
+package gherkintest
 
 import (
 	"bytes"
 	"context"
 	"fmt"
-	"github.com/cucumber/godog"
 	"io"
 	"log"
 	"net/http"
 	"time"
+
+	"github.com/cucumber/godog"
 )
 
+// RegisterApiSteps registers the steps sending requests to the app of the scenario and checking its responses
 func (s *StepsContext) RegisterApiSteps(sc *godog.ScenarioContext) {
+	sc.Step(`^API request header "([^"]*)" is "([^"]*)"$`, s.apiRequestHeaderIs)
 	sc.Step(`^API "([^"]*)" request is sent to "([^"]*)" without payload$`, s.apiRequestIsSendWithoutPayload)
 	sc.Step(`^API "([^"]*)" request is sent to "([^"]*)" with payload$`, s.apiRequestIsSendWithPayload)
 	sc.Step(`^API response status code is (\d+) and payload is$`, s.apiResponseIs)
+	sc.Step(`^API response status code is (\d+) without payload$`, s.apiResponseIsWithoutPayload)
+	sc.Step(`^API response header "([^"]*)" is "([^"]*)"$`, s.apiResponseHeaderIs)
+}
+
+func (s *StepsContext) apiRequestHeaderIs(name, value string) error {
+	s.stepRequestHeaders[name] = value
+	return nil
 }
 
 func (s *StepsContext) apiRequestIsSendWithoutPayload(method, url string) error {
@@ -32,6 +42,9 @@ func (s *StepsContext) apiRequestIsSendWithPayload(method, url, payloadJson stri
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	for name, value := range s.stepRequestHeaders {
+		req.Header.Set(name, value)
+	}
 
 	response, err := client.Do(req)
 	if err != nil {
@@ -64,6 +77,32 @@ func (s *StepsContext) apiResponseIs(expected int, expectedResponse string) erro
 	return nil
 }
 
+func (s *StepsContext) apiResponseIsWithoutPayload(expected int) error {
+	defer s.stepResponse.Body.Close()
+
+	if s.stepResponse.StatusCode != expected {
+		return fmt.Errorf("expected status code %d but got %d", expected, s.stepResponse.StatusCode)
+	}
+
+	actualBody, err := getBody(s.stepResponse)
+	if err != nil {
+		return err
+	}
+
+	if actualBody != "" {
+		return fmt.Errorf("expected an empty response body but got: %s", actualBody)
+	}
+
+	return nil
+}
+
+func (s *StepsContext) apiResponseHeaderIs(name, expected string) error {
+	if actual := s.stepResponse.Header.Get(name); actual != expected {
+		return fmt.Errorf("expected response header %s to be %q but got %q", name, expected, actual)
+	}
+	return nil
+}
+
 func getBody(response *http.Response) (string, error) {
 	body, err := io.ReadAll(response.Body)
 	if err != nil {
@@ -0,0 +1,66 @@
+package gherkintest
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+
+	"github.com/cucumber/godog"
+	"github.com/jarcoal/httpmock"
+)
+
+// StepsContext is the state of the steps of a scenario, see NewStepsContext
+type StepsContext struct {
+	// Main setup, filled before every scenario with the app of the scenario
+	mainHttpServerUrl string // http://127.0.0.1:41234
+	database          *sql.DB
+	mockTransport     *httpmock.MockTransport
+	// Mock server setup
+	stepMockServerRequestMethod *string
+	stepMockServerRequestUrl    *string
+	stepMockServerRequestBody   *string
+	// stepMockServerRequestHeaders are the headers the request must have
+	stepMockServerRequestHeaders map[string]string
+	// API setup
+	stepRequestHeaders map[string]string
+	stepResponse       *http.Response
+}
+
+// NewStepsContext registers the steps of a scenario, which runs against its own app started with newApp and the tags of
+// the scenario
+func NewStepsContext(newApp func(ctx context.Context, tags []string) (*ScenarioApp, error), sc *godog.ScenarioContext) *StepsContext {
+	s := &StepsContext{
+		stepRequestHeaders: map[string]string{},
+	}
+	var app *ScenarioApp
+	sc.Before(func(ctx context.Context, scenario *godog.Scenario) (context.Context, error) {
+		tags := make([]string, 0, len(scenario.Tags))
+		for _, tag := range scenario.Tags {
+			tags = append(tags, tag.Name)
+		}
+		var err error
+		if app, err = newApp(ctx, tags); err != nil {
+			return ctx, err
+		}
+		s.mainHttpServerUrl = app.URL
+		s.database = app.Database
+		s.mockTransport = app.MockTransport
+		return ctx, nil
+	})
+	sc.After(func(ctx context.Context, _ *godog.Scenario, _ error) (context.Context, error) {
+		if app == nil {
+			return ctx, nil
+		}
+		return ctx, app.Close(ctx)
+	})
+	// Register all the step definition function
+	s.RegisterMockServerSteps(sc)
+	s.RegisterDatabaseSteps(sc)
+	s.RegisterApiSteps(sc)
+	return s
+}
+
+// Database returns the connection to the database of the running scenario, nil before the scenario starts
+func (s *StepsContext) Database() *sql.DB {
+	return s.database
+}
@@ -1,16 +1,26 @@
-package main
+package gherkintest
 
 import (
 	"encoding/json"
 	"fmt"
-	"github.com/cucumber/godog"
 	"strings"
+	"time"
+
+	"github.com/cucumber/godog"
 )
 
+// eventuallyTimeout bounds the wait for the background work, such as the email outbox, to reach the expected state
+const (
+	eventuallyTimeout      = 5 * time.Second
+	eventuallyPollInterval = 100 * time.Millisecond
+)
+
+// RegisterDatabaseSteps registers the steps running SQL commands and checking SQL queries on the database of the scenario
 func (s *StepsContext) RegisterDatabaseSteps(sc *godog.ScenarioContext) {
 	sc.Step(`^SQL command`, s.executeSQL)
 	sc.Step(`^SQL query "([^"]*)" result is equal to`, s.checkSQLqueryWithoutIgnore)
 	sc.Step(`^SQL query "([^"]*)" result without the fields "([^"]*)" is equal to`, s.checkSQLqueryWithIgnoredFields)
+	sc.Step(`^SQL query "([^"]*)" result is eventually equal to`, s.checkSQLqueryEventually)
 }
 
 func (s *StepsContext) executeSQL(sqlCommand string) error {
@@ -22,7 +32,19 @@ func (s *StepsContext) executeSQL(sqlCommand string) error {
 }
 
 func (s *StepsContext) checkSQLqueryWithoutIgnore(query, jsonString string) error {
-	return s.checkSQLqueryWithIgnoredFields(query, jsonString, "")
+	return s.checkSQLqueryWithIgnoredFields(query, "", jsonString)
+}
+
+// checkSQLqueryEventually retries the query until the result matches or eventuallyTimeout expires
+func (s *StepsContext) checkSQLqueryEventually(query, jsonString string) error {
+	deadline := time.Now().Add(eventuallyTimeout)
+	for {
+		err := s.checkSQLqueryWithoutIgnore(query, jsonString)
+		if err == nil || time.Now().After(deadline) {
+			return err
+		}
+		time.Sleep(eventuallyPollInterval)
+	}
 }
 
 func (s *StepsContext) checkSQLqueryWithIgnoredFields(query, ignoredFields, jsonString string) error {
@@ -94,13 +116,16 @@ func (s *StepsContext) checkSQLqueryWithIgnoredFields(query, ignoredFields, json
 			delete(expectedData[i], ignoredField)
 		}
 	}
-	// Convert query result to JSON
+	// Convert query result and the expected data without the ignored fields to JSON
 	queryResultJSON, err := json.Marshal(resultRows)
 	if err != nil {
 		return fmt.Errorf("error marshalling query result to JSON: %w", err)
 	}
-	//
-	if match, err := compareJSONArrays(jsonString, string(queryResultJSON)); err != nil {
+	expectedJSON, err := json.Marshal(expectedData)
+	if err != nil {
+		return fmt.Errorf("error marshalling expected data to JSON: %w", err)
+	}
+	if match, err := compareJSONArrays(string(expectedJSON), string(queryResultJSON)); err != nil {
 		return fmt.Errorf("error comparing JSON: %w", err)
 	} else if !match {
 
@@ -0,0 +1,135 @@
+package gherkintest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/cucumber/godog"
+	"github.com/jarcoal/httpmock"
+)
+
+// RegisterMockServerSteps registers all the step definition functions related to the mock server
+func (s *StepsContext) RegisterMockServerSteps(ctx *godog.ScenarioContext) {
+	ctx.Step(`^a mock server request with method: "([^"]*)" and url: "([^"]*)"$`, s.storeMockServerMethodAndUrlInStepContext)
+	ctx.Step(`^a mock server request with method: "([^"]*)" and url: "([^"]*)" and body$`, s.storeMockServerMethodAndUrlAndRequestBodyInStepContext)
+	ctx.Step(`^the mock server request has header "([^"]*)" with value "([^"]*)"$`, s.storeMockServerRequestHeaderInStepContext)
+	ctx.Step(`^a mock server response with status (\d+) and body$`, s.setupRegisterResponder)
+	ctx.Step(`^a mock server response with status (\d+) after (\d+) milliseconds and body$`, s.setupRegisterDelayedResponder)
+	ctx.Step(`^a mock server response with status (\d+) and a body that panics when read$`, s.setupRegisterPanickingResponder)
+	ctx.Step(`^reset mock server$`, s.resetMockServer)
+}
+
+func (s *StepsContext) storeMockServerMethodAndUrlInStepContext(method, url string) error {
+	s.stepMockServerRequestMethod = &method
+	s.stepMockServerRequestUrl = &url
+	s.stepMockServerRequestBody = nil
+	s.stepMockServerRequestHeaders = nil
+	return nil
+}
+
+func (s *StepsContext) storeMockServerMethodAndUrlAndRequestBodyInStepContext(method, url, body string) error {
+	s.stepMockServerRequestMethod = &method
+	s.stepMockServerRequestUrl = &url
+	s.stepMockServerRequestBody = &body
+	s.stepMockServerRequestHeaders = nil
+	return nil
+}
+
+func (s *StepsContext) storeMockServerRequestHeaderInStepContext(name, value string) error {
+	if s.stepMockServerRequestHeaders == nil {
+		s.stepMockServerRequestHeaders = map[string]string{}
+	}
+	s.stepMockServerRequestHeaders[name] = value
+	return nil
+}
+
+func (s *StepsContext) setupRegisterResponder(statusCode int, responseBody string) error {
+	return s.registerResponder(func() *http.Response {
+		return httpmock.NewStringResponse(statusCode, responseBody)
+	}, 0)
+}
+
+// setupRegisterDelayedResponder answers after the delay, unless the request is cancelled before
+func (s *StepsContext) setupRegisterDelayedResponder(statusCode, delayMilliseconds int, responseBody string) error {
+	return s.registerResponder(func() *http.Response {
+		return httpmock.NewStringResponse(statusCode, responseBody)
+	}, time.Duration(delayMilliseconds)*time.Millisecond)
+}
+
+// setupRegisterPanickingResponder answers with a body whose reads panic, so the app panics where it reads the response
+func (s *StepsContext) setupRegisterPanickingResponder(statusCode int) error {
+	return s.registerResponder(func() *http.Response {
+		response := httpmock.NewStringResponse(statusCode, "")
+		response.Body = panickingBody{}
+		return response
+	}, 0)
+}
+
+func (s *StepsContext) registerResponder(newResponse func() *http.Response, delay time.Duration) error {
+	if s.stepMockServerRequestMethod == nil || s.stepMockServerRequestUrl == nil {
+		return errors.New("stepMockServerRequestMethod or stepMockServerRequestUrl is nil. You have to setup the storeMockServerMethodAndUrlInStepContext step first")
+	}
+	responder := s.responderWithChecks(newResponse)
+	if delay > 0 {
+		responder = responder.Delay(delay)
+	}
+	s.mockTransport.RegisterResponder(*s.stepMockServerRequestMethod, *s.stepMockServerRequestUrl, responder)
+	return nil
+}
+
+// responderWithChecks returns a responder checking the expected body and headers of the request, if any
+func (s *StepsContext) responderWithChecks(newResponse func() *http.Response) httpmock.Responder {
+	// Copy the expectations, the step context is reused by the next steps
+	expectedBody := s.stepMockServerRequestBody
+	expectedHeaders := s.stepMockServerRequestHeaders
+	return func(req *http.Request) (*http.Response, error) {
+		for name, expected := range expectedHeaders {
+			if actual := req.Header.Get(name); actual != expected {
+				return nil, fmt.Errorf("request header %s does not match for method: %s and url: %s. Expected: %q, actual: %q",
+					name, req.Method, req.URL, expected, actual)
+			}
+		}
+		if expectedBody == nil {
+			return newResponse(), nil
+		}
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+
+		if match, err := compareJSON(*expectedBody, string(body)); err != nil {
+			return nil, fmt.Errorf("error comparing JSON: %w", err)
+		} else if !match {
+			log.Printf("Actual request body without escapes: %s", body)
+			return nil, fmt.Errorf("request body does not match for method: %s and url: %s. Expected: %s, actual: %s",
+				req.Method, req.URL, *expectedBody, string(body))
+		}
+
+		return newResponse(), nil
+	}
+}
+
+// panickingBody is a response body whose reads panic
+type panickingBody struct{}
+
+func (panickingBody) Read([]byte) (int, error) {
+	panic("gherkintest: the mock server response body panics when read")
+}
+
+func (panickingBody) Close() error {
+	return nil
+}
+
+func (s *StepsContext) resetMockServer() error {
+	s.mockTransport.Reset()
+	// Reset the step context
+	s.stepMockServerRequestMethod = nil
+	s.stepMockServerRequestUrl = nil
+	s.stepMockServerRequestBody = nil
+	s.stepMockServerRequestHeaders = nil
+	return nil
+}
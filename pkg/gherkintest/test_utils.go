@@ -1,4 +1,4 @@
-package main
+package gherkintest
 
 import (
 	"encoding/json"
@@ -0,0 +1,158 @@
+package gherkintest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/docker/go-connections/nat"
+	_ "github.com/lib/pq" // Import the postgres driver
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// TestContainersParams are the settings of the postgres container
+type TestContainersParams struct {
+	// PostgresImage is the image of the container, e.g. docker.io/postgres:16-alpine
+	PostgresImage string
+	// DatabaseName is the database migrated once and copied for every scenario
+	DatabaseName string
+	// Migrate creates the schema of the template database, it is skipped when nil
+	Migrate func(ctx context.Context, db *sql.DB) error
+	// TruncatedSchemas are the schemas whose tables are emptied before the app of every scenario is built
+	TruncatedSchemas []string
+}
+
+// Database is the connection settings of a postgres database
+type Database struct {
+	// Host and Port are the address of the container, as reached from the tests
+	Host string
+	Port int
+	// User and Password are the credentials of the superuser of the container
+	User     string
+	Password string
+	// Name is the name of the database
+	Name string
+}
+
+// DSN returns the connection string of the database for the postgres driver
+func (d Database) DSN() string {
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
+		d.Host, d.Port, d.User, d.Password, d.Name)
+}
+
+// TestContainersContext is the postgres container shared by the scenarios, each with its own database copied from the
+// migrated template. See NewScenarioApp.
+type TestContainersContext struct {
+	// Database is a connection to the maintenance database, where the databases of the scenarios are created
+	Database *sql.DB
+	// Params are the settings the container was started with
+	Params TestContainersParams
+	// template is the migrated database copied for every scenario
+	template Database
+	// databasesLock guards the databases of the scenarios
+	databasesLock sync.Mutex
+	databases     int
+	idleDatabases []string
+	scenarios     atomic.Int64
+	container     *postgres.PostgresContainer
+}
+
+// NewTestContainersContext starts the postgres container and migrates the template database of the scenarios.
+// Close must be called once the scenarios are done, even on error, to terminate the container.
+func NewTestContainersContext(ctx context.Context, params TestContainersParams) (*TestContainersContext, error) {
+	c := &TestContainersContext{
+		Params: params,
+	}
+	// Start the postgres container
+	container, template, err := initPostgresContainer(ctx, params)
+	c.container = container
+	c.template = template
+	if err != nil {
+		return c, err
+	}
+	// The template database must have no connection while it is copied, so connect to the maintenance database
+	maintenanceDatabase := template
+	maintenanceDatabase.Name = "postgres"
+	if c.Database, err = sql.Open("postgres", maintenanceDatabase.DSN()); err != nil {
+		return c, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	return c, nil
+}
+
+// Close closes the database connection and terminates the postgres container
+func (c *TestContainersContext) Close(ctx context.Context) error {
+	var errs []error
+	if c.Database != nil {
+		if err := c.Database.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close the database: %w", err))
+		}
+	}
+	if c.container != nil {
+		if err := c.container.Terminate(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to terminate the postgres container: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// initPostgresContainer starts a postgres container and migrates its database.
+// The container is returned even on error, when it was created, so it can be terminated.
+// Source: https://golang.testcontainers.org/modules/postgres/
+func initPostgresContainer(ctx context.Context, params TestContainersParams) (*postgres.PostgresContainer, Database, error) {
+	logTimeout := 10
+	port := "5432/tcp"
+	dbURL := func(host string, port nat.Port) string {
+		return fmt.Sprintf("postgres://postgres:postgres@%s:%s/%s?sslmode=disable",
+			host, port.Port(), url.PathEscape(params.DatabaseName))
+	}
+
+	postgresContainer, err := postgres.Run(ctx, params.PostgresImage,
+		postgres.WithDatabase(params.DatabaseName),
+		testcontainers.WithWaitStrategy(wait.ForSQL(nat.Port(port), "postgres", dbURL).
+			WithStartupTimeout(time.Duration(logTimeout)*time.Second)),
+	)
+	if err != nil {
+		return postgresContainer, Database{}, fmt.Errorf("failed to start the postgres container: %w", err)
+	}
+	postgresHost, err := postgresContainer.Host(ctx)
+	if err != nil {
+		return postgresContainer, Database{}, fmt.Errorf("failed to get the postgres container host: %w", err)
+	}
+	mappedPort, err := postgresContainer.MappedPort(ctx, nat.Port(port))
+	if err != nil {
+		return postgresContainer, Database{}, fmt.Errorf("failed to get the postgres container port: %w", err)
+	}
+	database := Database{
+		Host:     postgresHost,
+		Port:     mappedPort.Int(),
+		User:     "postgres",
+		Password: "postgres",
+		Name:     params.DatabaseName,
+	}
+	log.Printf("Postgres container started at: %s:%d", database.Host, database.Port)
+	// Create the schema of the template database
+	return postgresContainer, database, migrateDatabase(ctx, database, params.Migrate)
+}
+
+func migrateDatabase(ctx context.Context, database Database, migrate func(ctx context.Context, db *sql.DB) error) error {
+	if migrate == nil {
+		return nil
+	}
+	db, err := sql.Open("postgres", database.DSN())
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+	if err = migrate(ctx, db); err != nil {
+		return fmt.Errorf("failed to migrate the database: %w", err)
+	}
+	return nil
+}